@@ -0,0 +1,290 @@
+package prefixmap
+
+import (
+  "iter"
+  "math/bits"
+  "net/netip"
+)
+
+// CIDRMap maps IP prefixes, as in 10.0.0.0/8 or 2001:db8::/32,
+// to values. It is a bit-granular radix tree holding IPv4 and IPv6
+// prefixes in separate trees.
+type CIDRMap[V any] struct {
+  v4, v6 *bitNode[V]
+  len    int
+}
+
+// bitNode is a node of a bit-granular radix tree.
+// As for Node, a node is split when inserting a prefix diverging
+// from its own and the tree is compacted on deletion.
+type bitNode[V any] struct {
+  parent   *bitNode[V]
+  children [2]*bitNode[V]
+
+  // the whole prefix up to the node,
+  // with the host bits masked
+  prefix netip.Prefix
+  isKey  bool
+  value  V
+}
+
+// NewCIDRMap returns a new empty CIDRMap
+func NewCIDRMap[V any]() *CIDRMap[V] {
+  return &CIDRMap[V]{
+    v4: &bitNode[V]{prefix: netip.PrefixFrom(netip.IPv4Unspecified(), 0)},
+    v6: &bitNode[V]{prefix: netip.PrefixFrom(netip.IPv6Unspecified(), 0)},
+  }
+}
+
+// Insert maps the given prefix to value, replacing the
+// existing value if any. The host bits of the prefix are
+// ignored and invalid prefixes are not inserted.
+func (c *CIDRMap[V]) Insert(prefix netip.Prefix, value V) {
+  if !prefix.IsValid() {
+    return
+  }
+  prefix = prefix.Masked()
+
+  node := c.root(prefix.Addr())
+  for node.prefix.Bits() < prefix.Bits() {
+    b := addrBit(prefix.Addr(), node.prefix.Bits())
+    child := node.children[b]
+    if child == nil {
+      child = &bitNode[V]{parent: node, prefix: prefix}
+      node.children[b] = child
+    } else if common := commonBits(child.prefix.Addr(), prefix.Addr(), min(child.prefix.Bits(), prefix.Bits())); common < child.prefix.Bits() {
+      child.split(common)
+    }
+    node = child
+  }
+
+  if !node.isKey {
+    node.isKey = true
+    c.len++
+  }
+  node.value = value
+}
+
+// Get returns the value the given prefix maps to.
+// ok is false if no such prefix is present in the map.
+func (c *CIDRMap[V]) Get(prefix netip.Prefix) (value V, ok bool) {
+  if node := c.nodeFor(prefix); node != nil {
+    return node.value, true
+  }
+  return value, false
+}
+
+// Delete removes the given prefix from the map.
+// Returns false if no such prefix is present in the map.
+func (c *CIDRMap[V]) Delete(prefix netip.Prefix) bool {
+  node := c.nodeFor(prefix)
+  if node == nil {
+    return false
+  }
+
+  var zero V
+  node.isKey, node.value = false, zero
+  node.compact()
+  c.len--
+
+  return true
+}
+
+// Len returns the number of prefixes in the map
+func (c *CIDRMap[V]) Len() int {
+  return c.len
+}
+
+// Lookup returns the most specific prefix in the map
+// containing the given address, along with its value.
+// ok is false if no prefix contains the address.
+func (c *CIDRMap[V]) Lookup(addr netip.Addr) (prefix netip.Prefix, value V, ok bool) {
+  if !addr.IsValid() {
+    return
+  }
+  addr = addr.WithZone("")
+
+  for prefix, value = range c.Covering(netip.PrefixFrom(addr, addr.BitLen())) {
+    ok = true
+  }
+  return
+}
+
+// Covering returns an iterator over the prefixes in the map
+// containing the given one, itself included, and their values,
+// from the least to the most specific
+func (c *CIDRMap[V]) Covering(prefix netip.Prefix) iter.Seq2[netip.Prefix, V] {
+  return func(yield func(netip.Prefix, V) bool) {
+    if !prefix.IsValid() {
+      return
+    }
+    prefix = prefix.Masked()
+
+    node := c.root(prefix.Addr())
+    for node != nil && node.prefix.Bits() <= prefix.Bits() && node.prefix.Contains(prefix.Addr()) {
+      if node.isKey && !yield(node.prefix, node.value) {
+        return
+      }
+      if node.prefix.Bits() == prefix.Bits() {
+        return
+      }
+      node = node.children[addrBit(prefix.Addr(), node.prefix.Bits())]
+    }
+  }
+}
+
+// Covered returns an iterator over the prefixes in the map
+// contained in the given one, itself included, and their values,
+// in ascending order
+func (c *CIDRMap[V]) Covered(prefix netip.Prefix) iter.Seq2[netip.Prefix, V] {
+  return func(yield func(netip.Prefix, V) bool) {
+    if !prefix.IsValid() {
+      return
+    }
+    prefix = prefix.Masked()
+
+    node := c.root(prefix.Addr())
+    for node.prefix.Bits() < prefix.Bits() {
+      node = node.children[addrBit(prefix.Addr(), node.prefix.Bits())]
+      if node == nil {
+        return
+      }
+      if node.prefix.Bits() < prefix.Bits() && !node.prefix.Contains(prefix.Addr()) {
+        return
+      }
+    }
+    if prefix.Contains(node.prefix.Addr()) {
+      node.each(yield)
+    }
+  }
+}
+
+// All returns an iterator over the prefixes in the map and
+// their values in ascending order, that is by address and then
+// from the least to the most specific, IPv4 prefixes first
+func (c *CIDRMap[V]) All() iter.Seq2[netip.Prefix, V] {
+  return func(yield func(netip.Prefix, V) bool) {
+    if c.v4.each(yield) {
+      c.v6.each(yield)
+    }
+  }
+}
+
+// root returns the root of the tree holding
+// the prefixes of the family of addr
+func (c *CIDRMap[V]) root(addr netip.Addr) *bitNode[V] {
+  if addr.Is4() {
+    return c.v4
+  }
+  return c.v6
+}
+
+// nodeFor returns the node holding the
+// given prefix or nil if no such node exists
+func (c *CIDRMap[V]) nodeFor(prefix netip.Prefix) *bitNode[V] {
+  if !prefix.IsValid() {
+    return nil
+  }
+  prefix = prefix.Masked()
+
+  node := c.root(prefix.Addr())
+  for node != nil && node.prefix.Bits() < prefix.Bits() {
+    node = node.children[addrBit(prefix.Addr(), node.prefix.Bits())]
+  }
+  if node == nil || node.prefix != prefix || !node.isKey {
+    return nil
+  }
+
+  return node
+}
+
+// split makes n hold the first bits of its prefix,
+// moving its contents to a new child node
+func (n *bitNode[V]) split(bits int) {
+  subNode := &bitNode[V]{}
+  *subNode = *n
+  subNode.parent = n
+
+  // adjusting children parent
+  for _, child := range subNode.children {
+    if child != nil {
+      child.parent = subNode
+    }
+  }
+
+  var zero V
+  n.prefix = netip.PrefixFrom(n.prefix.Addr(), bits).Masked()
+  n.children = [2]*bitNode[V]{}
+  n.children[addrBit(subNode.prefix.Addr(), bits)] = subNode
+  n.isKey, n.value = false, zero
+}
+
+// compact prunes n if it holds no prefix and has
+// no children, along with its ancestors in the same
+// state, or merges it with its only child
+func (n *bitNode[V]) compact() {
+  node := n
+  for node.parent != nil && !node.isKey {
+    parent := node.parent
+    i := addrBit(node.prefix.Addr(), parent.prefix.Bits())
+
+    switch {
+    case node.children[0] == nil && node.children[1] == nil:
+      parent.children[i] = nil
+      node = parent
+      continue
+    case node.children[0] == nil:
+      parent.children[i] = node.children[1]
+    case node.children[1] == nil:
+      parent.children[i] = node.children[0]
+    default:
+      return
+    }
+    parent.children[i].parent = parent
+    return
+  }
+}
+
+// each yields the prefixes in the subtree rooted at n
+// in ascending order. Returns false if yield stopped.
+func (n *bitNode[V]) each(yield func(netip.Prefix, V) bool) bool {
+  if n.isKey && !yield(n.prefix, n.value) {
+    return false
+  }
+  for _, child := range n.children {
+    if child != nil && !child.each(yield) {
+      return false
+    }
+  }
+  return true
+}
+
+// addrBit returns the i-th most significant bit of addr
+func addrBit(addr netip.Addr, i int) int {
+  if addr.Is4() {
+    i += 96
+  }
+  b := addr.As16()
+  return int(b[i/8]>>(7-i%8)) & 1
+}
+
+// commonBits returns the number of leading bits
+// a and b have in common, up to limit
+func commonBits(a, b netip.Addr, limit int) int {
+  offset := 0
+  if a.Is4() {
+    offset = 12
+  }
+  a16, b16 := a.As16(), b.As16()
+
+  common := 0
+  for i := offset; i < len(a16) && common < limit; i++ {
+    if x := a16[i] ^ b16[i]; x != 0 {
+      common += bits.LeadingZeros8(x)
+      break
+    }
+    common += 8
+  }
+
+  return min(common, limit)
+}
@@ -0,0 +1,190 @@
+package prefixmap
+
+import (
+  "net/netip"
+  "testing"
+)
+
+func testPrefixes(seq func(func(netip.Prefix, string) bool)) []string {
+  prefixes := []string{}
+  for prefix, value := range seq {
+    if prefix.String() != value {
+      return append(prefixes, "unexpected value "+value)
+    }
+    prefixes = append(prefixes, value)
+  }
+  return prefixes
+}
+
+var cidrPrefixes = []string{
+  "10.0.0.0/8",
+  "10.1.0.0/16",
+  "10.1.2.0/24",
+  "10.128.0.0/9",
+  "0.0.0.0/0",
+  "192.168.0.0/16",
+  "192.168.1.0/24",
+  "2001:db8::/32",
+  "2001:db8:1::/48",
+  "::/0",
+}
+
+func TestCIDRLookup(t *testing.T) {
+  c := NewCIDRMap[string]()
+  for _, prefix := range cidrPrefixes {
+    c.Insert(netip.MustParsePrefix(prefix), prefix)
+  }
+  if c.Len() != len(cidrPrefixes) {
+    t.Errorf("Unexpected length: got %d, expected %d", c.Len(), len(cidrPrefixes))
+  }
+
+  testCases := []struct {
+    addr, expected string
+  }{
+    {"10.1.2.3", "10.1.2.0/24"},
+    {"10.1.3.3", "10.1.0.0/16"},
+    {"10.2.3.4", "10.0.0.0/8"},
+    {"10.200.0.1", "10.128.0.0/9"},
+    {"192.168.1.1", "192.168.1.0/24"},
+    {"192.169.1.1", "0.0.0.0/0"},
+    {"2001:db8:1::1", "2001:db8:1::/48"},
+    {"2001:db8:2::1", "2001:db8::/32"},
+    {"fe80::1%eth0", "::/0"},
+  }
+
+  for _, tc := range testCases {
+    prefix, value, ok := c.Lookup(netip.MustParseAddr(tc.addr))
+    if !ok || prefix.String() != tc.expected || value != tc.expected {
+      t.Errorf("Unexpected lookup of '%s': got (%v, %s, %v), expected %s", tc.addr, prefix, value, ok, tc.expected)
+    }
+  }
+
+  c = NewCIDRMap[string]()
+  c.Insert(netip.MustParsePrefix("10.0.0.0/8"), "10.0.0.0/8")
+  if _, _, ok := c.Lookup(netip.MustParseAddr("11.0.0.1")); ok {
+    t.Errorf("Expected no prefix to contain the address")
+  }
+  if _, _, ok := c.Lookup(netip.MustParseAddr("::1")); ok {
+    t.Errorf("Expected no IPv6 prefix to contain the address")
+  }
+}
+
+func TestCIDRGet(t *testing.T) {
+  c := NewCIDRMap[string]()
+  for _, prefix := range cidrPrefixes {
+    c.Insert(netip.MustParsePrefix(prefix), prefix)
+  }
+
+  if value, ok := c.Get(netip.MustParsePrefix("10.1.2.3/24")); !ok || value != "10.1.2.0/24" {
+    t.Errorf("Expected host bits to be ignored: got (%s, %v)", value, ok)
+  }
+  for _, prefix := range []string{"10.1.0.0/15", "10.0.0.0/9", "10.1.2.0/25", "::/1"} {
+    if _, ok := c.Get(netip.MustParsePrefix(prefix)); ok {
+      t.Errorf("Unexpected prefix '%s' found", prefix)
+    }
+  }
+
+  c.Insert(netip.MustParsePrefix("10.0.0.0/8"), "replaced")
+  if value, _ := c.Get(netip.MustParsePrefix("10.0.0.0/8")); value != "replaced" {
+    t.Errorf("Expected the value to be replaced: got %s", value)
+  }
+  if c.Len() != len(cidrPrefixes) {
+    t.Errorf("Unexpected length after replacing: got %d", c.Len())
+  }
+}
+
+func TestCIDRDelete(t *testing.T) {
+  c := NewCIDRMap[string]()
+  for _, prefix := range cidrPrefixes {
+    c.Insert(netip.MustParsePrefix(prefix), prefix)
+  }
+  for _, prefix := range cidrPrefixes {
+    if !c.Delete(netip.MustParsePrefix(prefix)) {
+      t.Errorf("Expected '%s' to be deleted", prefix)
+    }
+    if c.Delete(netip.MustParsePrefix(prefix)) {
+      t.Errorf("Expected '%s' to be deleted once", prefix)
+    }
+    if _, ok := c.Get(netip.MustParsePrefix(prefix)); ok {
+      t.Errorf("Unexpected prefix '%s' found after deletion", prefix)
+    }
+  }
+
+  if c.Len() != 0 {
+    t.Errorf("Unexpected length: got %d", c.Len())
+  }
+  if c.v4.children != c.v6.children || c.v4.children[0] != nil || c.v4.children[1] != nil {
+    t.Errorf("Expected the trees to be compacted")
+  }
+
+  c.Insert(netip.MustParsePrefix("10.1.0.0/16"), "10.1.0.0/16")
+  c.Insert(netip.MustParsePrefix("10.2.0.0/16"), "10.2.0.0/16")
+  c.Delete(netip.MustParsePrefix("10.1.0.0/16"))
+  if child := c.v4.children[0]; child == nil || child.prefix.String() != "10.2.0.0/16" || child.parent != c.v4 {
+    t.Errorf("Expected the split node to be merged: got %v", child)
+  }
+}
+
+func TestCIDRCovering(t *testing.T) {
+  c := NewCIDRMap[string]()
+  for _, prefix := range cidrPrefixes {
+    c.Insert(netip.MustParsePrefix(prefix), prefix)
+  }
+
+  testCases := []struct {
+    prefix   string
+    covering []string
+    covered  []string
+  }{
+    {"10.1.0.0/16", []string{"0.0.0.0/0", "10.0.0.0/8", "10.1.0.0/16"}, []string{"10.1.0.0/16", "10.1.2.0/24"}},
+    {"10.0.0.0/8", []string{"0.0.0.0/0", "10.0.0.0/8"}, []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "10.128.0.0/9"}},
+    {"10.1.0.0/15", []string{"0.0.0.0/0", "10.0.0.0/8"}, []string{"10.1.0.0/16", "10.1.2.0/24"}},
+    {"10.2.0.0/15", []string{"0.0.0.0/0", "10.0.0.0/8"}, []string{}},
+    {"192.168.0.0/15", []string{"0.0.0.0/0"}, []string{"192.168.0.0/16", "192.168.1.0/24"}},
+    {"2001:db8:1:2::/64", []string{"::/0", "2001:db8::/32", "2001:db8:1::/48"}, []string{}},
+    {"::/0", []string{"::/0"}, []string{"::/0", "2001:db8::/32", "2001:db8:1::/48"}},
+  }
+
+  for _, tc := range testCases {
+    prefix := netip.MustParsePrefix(tc.prefix)
+    if covering := testPrefixes(c.Covering(prefix)); !testStringsEq(covering, tc.covering) {
+      t.Errorf("Unexpected prefixes covering '%s': got %v, expected %v", tc.prefix, covering, tc.covering)
+    }
+    if covered := testPrefixes(c.Covered(prefix)); !testStringsEq(covered, tc.covered) {
+      t.Errorf("Unexpected prefixes covered by '%s': got %v, expected %v", tc.prefix, covered, tc.covered)
+    }
+  }
+}
+
+func TestCIDRAll(t *testing.T) {
+  c := NewCIDRMap[string]()
+  for _, prefix := range cidrPrefixes {
+    c.Insert(netip.MustParsePrefix(prefix), prefix)
+  }
+  expected := []string{
+    "0.0.0.0/0",
+    "10.0.0.0/8",
+    "10.1.0.0/16",
+    "10.1.2.0/24",
+    "10.128.0.0/9",
+    "192.168.0.0/16",
+    "192.168.1.0/24",
+    "::/0",
+    "2001:db8::/32",
+    "2001:db8:1::/48",
+  }
+  if prefixes := testPrefixes(c.All()); !testStringsEq(prefixes, expected) {
+    t.Errorf("Unexpected prefixes: got %v, expected %v", prefixes, expected)
+  }
+
+  count := 0
+  for range c.All() {
+    count++
+    if count == 3 {
+      break
+    }
+  }
+  if count != 3 {
+    t.Errorf("Expected iteration to stop early")
+  }
+}
@@ -0,0 +1,196 @@
+package prefixmap
+
+import (
+  "sort"
+  "strings"
+  "unicode/utf8"
+)
+
+// Cursor allows to navigate the map one byte at a time,
+// as in search-as-you-type scenarios, without looking up
+// the whole key from the root at each step.
+//
+// A cursor is positioned either on a node or within the key
+// of a node, since keys are compressed along the map edges.
+// In rune-aware maps, a cursor walking the first bytes of a rune
+// at a node boundary stays on the node until the rune is complete,
+// since several children may start with the same bytes.
+// Cursors walk normalized keys, if the map normalizes them.
+// Modifying the map invalidates its cursors.
+type Cursor[V any] struct {
+  node    *Node[V] // the node the cursor is on or within
+  offset  int      // number of bytes of node key walked so far
+  pending []byte   // the incomplete rune walked past node, if any
+  key     []byte   // the key walked so far
+}
+
+// Cursor returns a new cursor positioned
+// at the root of the map
+func (m *PrefixMap[V]) Cursor() *Cursor[V] {
+  return &Cursor[V]{
+    node: (*Node[V])(m),
+  }
+}
+
+// Advance moves the cursor forward by the given byte.
+// Returns false, leaving the cursor untouched, if no key
+// in the map continues the current one with b.
+func (c *Cursor[V]) Advance(b byte) bool {
+  if c.offset < len(c.node.key) {
+    if c.node.key[c.offset] != b {
+      return false
+    }
+    c.offset++
+    c.key = append(c.key, b)
+    return true
+  }
+
+  pending := append(c.pending, b)
+  if len(c.nextChildren(pending)) == 0 {
+    return false
+  }
+  if !c.node.opts.runes || utf8.FullRune(pending) {
+    c.node = c.node.childFor(string(pending))
+    c.offset = len(pending)
+    pending = pending[:0]
+  }
+  c.pending = pending
+  c.key = append(c.key, b)
+
+  return true
+}
+
+// AdvanceString moves the cursor forward by the given string.
+// Returns false, leaving the cursor untouched, if no key
+// in the map continues the current one with s.
+func (c *Cursor[V]) AdvanceString(s string) bool {
+  for i := 0; i < len(s); i++ {
+    if !c.Advance(s[i]) {
+      for ; i > 0; i-- {
+        c.Back()
+      }
+      return false
+    }
+  }
+
+  return true
+}
+
+// Back moves the cursor back by one byte.
+// Returns false if the cursor is at the root already.
+func (c *Cursor[V]) Back() bool {
+  if len(c.key) == 0 {
+    return false
+  }
+  c.key = c.key[:len(c.key)-1]
+  if len(c.pending) > 0 {
+    c.pending = c.pending[:len(c.pending)-1]
+    return true
+  }
+
+  c.offset--
+  if c.offset < c.node.opts.firstLen(c.node.key) && c.node.Parent != nil {
+    // back to the parent node, the bytes walked of
+    // the first rune of this node are pending
+    c.pending = append(c.pending, c.node.key[:c.offset]...)
+    c.node = c.node.Parent
+    c.offset = len(c.node.key)
+  }
+
+  return true
+}
+
+// Reset moves the cursor back to the root
+func (c *Cursor[V]) Reset() {
+  for c.node.Parent != nil {
+    c.node = c.node.Parent
+  }
+  c.offset = 0
+  c.pending = c.pending[:0]
+  c.key = c.key[:0]
+}
+
+// Key returns the key walked so far
+func (c *Cursor[V]) Key() string {
+  return string(c.key)
+}
+
+// IsKey returns true if the key walked
+// so far is present in the map
+func (c *Cursor[V]) IsKey() bool {
+  return len(c.pending) == 0 && c.offset == len(c.node.key) && c.node.isKey
+}
+
+// Values returns the values associated with the key
+// walked so far or nil if it is not present in the map
+func (c *Cursor[V]) Values() []V {
+  if !c.IsKey() {
+    return nil
+  }
+  return c.node.data
+}
+
+// Children returns the bytes the cursor
+// can be advanced by, in ascending order
+func (c *Cursor[V]) Children() []byte {
+  if c.offset < len(c.node.key) {
+    return []byte{c.node.key[c.offset]}
+  }
+
+  // children are sorted: bytes shared
+  // by several of them are contiguous
+  children := []byte{}
+  for _, child := range c.nextChildren(c.pending) {
+    b := child.key[len(c.pending)]
+    if len(children) == 0 || children[len(children)-1] != b {
+      children = append(children, b)
+    }
+  }
+
+  return children
+}
+
+// Completions returns the keys starting with the key walked
+// so far, in ascending lexicographic order.
+// At most limit keys are returned, all of them if limit <= 0.
+func (c *Cursor[V]) Completions(limit int) []Prefix[V] {
+  completions := []Prefix[V]{}
+  callback := func(node *Node[V], key string) bool {
+    completions = append(completions, Prefix[V]{
+      node:   node,
+      Key:    node.keyOf(key),
+      Values: node.data,
+    })
+    return limit <= 0 || len(completions) < limit
+  }
+
+  if len(c.pending) == 0 {
+    parentKey := append([]byte(nil), c.key[:len(c.key)-c.offset]...)
+    c.node.ascendNodes(parentKey, callback)
+    return completions
+  }
+
+  parentKey := c.key[:len(c.key)-len(c.pending)]
+  for _, child := range c.nextChildren(c.pending) {
+    if !child.ascendNodes(append([]byte(nil), parentKey...), callback) {
+      break
+    }
+  }
+
+  return completions
+}
+
+// nextChildren returns the children of the
+// cursor node whose key starts with the given bytes
+func (c *Cursor[V]) nextChildren(prefix []byte) []*Node[V] {
+  children := c.node.Children
+  i := sort.Search(len(children), func(i int) bool {
+    return children[i].key >= string(prefix)
+  })
+  j := i
+  for j < len(children) && strings.HasPrefix(children[j].key, string(prefix)) {
+    j++
+  }
+
+  return children[i:j]
+}
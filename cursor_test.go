@@ -0,0 +1,91 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestCursor(t *testing.T) {
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  c := m.Cursor()
+  for i, b := range []byte("roman") {
+    if !c.Advance(b) {
+      t.Fatalf("Cannot advance cursor by '%c' at %d", b, i)
+    }
+  }
+  if c.Key() != "roman" || c.IsKey() || c.Values() != nil {
+    t.Errorf("Unexpected cursor state: key '%s', isKey %v, values %v", c.Key(), c.IsKey(), c.Values())
+  }
+  if children := string(c.Children()); children != "eu" {
+    t.Errorf("Unexpected cursor children: got '%s', expected '%s'", children, "eu")
+  }
+
+  if c.Advance('x') || c.Key() != "roman" {
+    t.Errorf("Cursor is not expected to advance by 'x'")
+  }
+
+  if !c.Advance('e') || !c.IsKey() || testEq(c.Values(), []interface{}{"romane"}) != true {
+    t.Errorf("Unexpected cursor state on 'romane': isKey %v, values %v", c.IsKey(), c.Values())
+  }
+
+  // going back to 'ro', within the 'rom' node
+  for i := 0; i < 4; i++ {
+    if !c.Back() {
+      t.Fatalf("Cannot move cursor back at step %d", i)
+    }
+  }
+  if c.Key() != "ro" {
+    t.Errorf("Unexpected cursor key: got '%s', expected '%s'", c.Key(), "ro")
+  }
+  if children := string(c.Children()); children != "m" {
+    t.Errorf("Unexpected cursor children: got '%s', expected '%s'", children, "m")
+  }
+
+  if !c.AdvanceString("mulus") || !c.IsKey() {
+    t.Errorf("Cannot advance cursor to 'romulus'")
+  }
+
+  c.Reset()
+  if c.Key() != "" || c.Back() {
+    t.Errorf("Cursor is expected to be at the root after a reset")
+  }
+  if c.AdvanceString("rubix") || c.Key() != "" {
+    t.Errorf("Cursor is not expected to advance by 'rubix': got key '%s'", c.Key())
+  }
+}
+
+func TestCursorCompletions(t *testing.T) {
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  testCases := []struct {
+    key      string
+    limit    int
+    expected []string
+  }{
+    {"rub", 0, []string{"rubens", "ruber", "rubicon", "rubicundus"}},
+    {"rub", 2, []string{"rubens", "ruber"}},
+    {"rubico", 0, []string{"rubicon"}},
+    {"", 2, []string{"A", "romane"}},
+  }
+
+  for _, tc := range testCases {
+    c := m.Cursor()
+    if !c.AdvanceString(tc.key) {
+      t.Fatalf("Cannot advance cursor to '%s'", tc.key)
+    }
+
+    keys := []string{}
+    for _, p := range c.Completions(tc.limit) {
+      keys = append(keys, p.Key)
+    }
+    if !testStringsEq(keys, tc.expected) {
+      t.Errorf("Unexpected completions for '%s' (limit %d): got %v, expected %v", tc.key, tc.limit, keys, tc.expected)
+    }
+  }
+}
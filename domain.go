@@ -0,0 +1,135 @@
+package prefixmap
+
+import (
+  "iter"
+  "strings"
+)
+
+// DomainMap maps domain names to values. Names are stored with
+// their labels in reverse order, as in "com.example.www", so that
+// the names sharing a suffix share a branch of the map.
+// Names are case-insensitive and a trailing dot is ignored.
+//
+// Names whose first label is "*", as in "*.example.com", are
+// wildcards matching any subdomain of the rest of the name.
+type DomainMap[V any] struct {
+  m *SegmentedPrefixMap[V]
+}
+
+// NewDomainMap returns a new empty DomainMap
+func NewDomainMap[V any]() *DomainMap[V] {
+  return &DomainMap[V]{
+    m: NewSegmented[V]("."),
+  }
+}
+
+// Insert inserts new values in the map for the specified domain,
+// appending them to the existing ones if any
+func (d *DomainMap[V]) Insert(domain string, values ...V) {
+  d.m.Insert(reverseDomain(domain), values...)
+}
+
+// Replace replaces the value(s) for the given domain in the map
+func (d *DomainMap[V]) Replace(domain string, values ...V) {
+  d.m.Replace(reverseDomain(domain), values...)
+}
+
+// Get returns the data associated with the given domain in the
+// map or nil if no such domain is present in the map.
+// Wildcards are not expanded: Get("*.example.com") returns the
+// values of the wildcard itself.
+func (d *DomainMap[V]) Get(domain string) []V {
+  return d.m.Get(reverseDomain(domain))
+}
+
+// Contains checks if the given domain is present in the map
+func (d *DomainMap[V]) Contains(domain string) bool {
+  return d.m.Contains(reverseDomain(domain))
+}
+
+// Delete removes the given domain and its values from the map.
+// Returns false if no such domain is present in the map.
+func (d *DomainMap[V]) Delete(domain string) bool {
+  return d.m.Delete(reverseDomain(domain))
+}
+
+// Len returns the number of domains in the map
+func (d *DomainMap[V]) Len() int {
+  return d.m.Len()
+}
+
+// Lookup returns the domain in the map matching the given host,
+// along with its values: the host itself if present, or else the
+// most specific wildcard matching it. A wildcard does not match
+// its own parent domain: "*.example.com" does not match "example.com".
+// ok is false if no domain matches the host.
+func (d *DomainMap[V]) Lookup(host string) (domain string, values []V, ok bool) {
+  key := reverseDomain(host)
+  if values := d.m.Get(key); values != nil {
+    return reverseDomain(key), values, true
+  }
+
+  for i := strings.LastIndexByte(key, '.'); i >= 0; i = strings.LastIndexByte(key[:i], '.') {
+    wildcard := key[:i] + ".*"
+    if values := d.m.Get(wildcard); values != nil {
+      return reverseDomain(wildcard), values, true
+    }
+  }
+
+  return "", nil, false
+}
+
+// LongestSuffix returns the most specific domain in the map
+// being the given host or one of its parent domains, along with
+// its values. Wildcards are matched as regular domains.
+// ok is false if there's no such domain.
+func (d *DomainMap[V]) LongestSuffix(host string) (domain string, values []V, ok bool) {
+  key, values, ok := d.m.LongestPrefix(reverseDomain(host))
+  if !ok {
+    return "", nil, false
+  }
+  return reverseDomain(key), values, true
+}
+
+// GetBySuffix returns a flattened collection of the values
+// associated with the given domain and its subdomains,
+// sorted by reversed domain
+func (d *DomainMap[V]) GetBySuffix(domain string) []V {
+  return d.m.GetByPrefix(reverseDomain(domain))
+}
+
+// All returns an iterator over the domains in the map and
+// their values, sorted by reversed domain, so that each
+// domain immediately precedes its subdomains
+func (d *DomainMap[V]) All() iter.Seq2[string, []V] {
+  return func(yield func(string, []V) bool) {
+    for key, values := range d.m.All() {
+      if !yield(reverseDomain(key), values) {
+        return
+      }
+    }
+  }
+}
+
+// reverseDomain returns the lower case domain
+// with its labels in reverse order and without
+// a trailing dot. It is its own inverse.
+func reverseDomain(domain string) string {
+  domain = ASCIIFold(strings.TrimSuffix(domain, "."))
+  if strings.IndexByte(domain, '.') < 0 {
+    return domain
+  }
+
+  var b strings.Builder
+  b.Grow(len(domain))
+  for end := len(domain); end >= 0; {
+    start := strings.LastIndexByte(domain[:end], '.')
+    b.WriteString(domain[start+1 : end])
+    if start >= 0 {
+      b.WriteByte('.')
+    }
+    end = start
+  }
+
+  return b.String()
+}
@@ -0,0 +1,108 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestReverseDomain(t *testing.T) {
+  testCases := []struct {
+    domain, expected string
+  }{
+    {"www.example.com", "com.example.www"},
+    {"WWW.Example.COM.", "com.example.www"},
+    {"*.example.com", "com.example.*"},
+    {"localhost", "localhost"},
+    {"", ""},
+  }
+
+  for _, tc := range testCases {
+    if reversed := reverseDomain(tc.domain); reversed != tc.expected {
+      t.Errorf("Unexpected reversed domain for '%s': got '%s', expected '%s'", tc.domain, reversed, tc.expected)
+    }
+  }
+}
+
+func TestDomainLookup(t *testing.T) {
+  d := NewDomainMap[string]()
+  for _, domain := range []string{"example.com", "*.example.com", "www.example.com", "*.api.example.com", "*.org"} {
+    d.Insert(domain, domain)
+  }
+
+  testCases := []struct {
+    host, domain string
+    ok           bool
+  }{
+    {"www.example.com", "www.example.com", true},
+    {"WWW.EXAMPLE.COM.", "www.example.com", true},
+    {"example.com", "example.com", true},
+    {"mail.example.com", "*.example.com", true},
+    {"a.b.example.com", "*.example.com", true},
+    {"v1.api.example.com", "*.api.example.com", true},
+    {"api.example.com", "*.example.com", true},
+    {"golang.org", "*.org", true},
+    {"org", "", false},
+    {"example.net", "", false},
+    {"badexample.com", "", false},
+  }
+
+  for _, tc := range testCases {
+    domain, values, ok := d.Lookup(tc.host)
+    if domain != tc.domain || ok != tc.ok || (ok && values[0] != tc.domain) {
+      t.Errorf("Unexpected lookup of '%s': got ('%s', %v, %v), expected '%s'", tc.host, domain, values, ok, tc.domain)
+    }
+  }
+}
+
+func TestDomainLongestSuffix(t *testing.T) {
+  d := NewDomainMap[string]()
+  for _, domain := range []string{"com", "example.com", "www.example.com"} {
+    d.Insert(domain, domain)
+  }
+
+  testCases := []struct {
+    host, domain string
+    ok           bool
+  }{
+    {"www.example.com", "www.example.com", true},
+    {"a.www.example.com.", "www.example.com", true},
+    {"mail.Example.com", "example.com", true},
+    {"badexample.com", "com", true},
+    {"example.org", "", false},
+  }
+
+  for _, tc := range testCases {
+    domain, _, ok := d.LongestSuffix(tc.host)
+    if domain != tc.domain || ok != tc.ok {
+      t.Errorf("Unexpected longest suffix of '%s': got ('%s', %v), expected '%s'", tc.host, domain, ok, tc.domain)
+    }
+  }
+}
+
+func TestDomainAll(t *testing.T) {
+  d := NewDomainMap[string]()
+  for _, domain := range []string{"www.example.com", "example.com.", "Mail.Example.com", "example.org", "badexample.com"} {
+    d.Insert(domain, domain)
+  }
+  if !d.Contains("mail.example.com") || d.Len() != 5 {
+    t.Errorf("Expected domains to be case-insensitive")
+  }
+
+  domains := []string{}
+  for domain := range d.All() {
+    domains = append(domains, domain)
+  }
+  expected := []string{"badexample.com", "example.com", "mail.example.com", "www.example.com", "example.org"}
+  if !testStringsEq(domains, expected) {
+    t.Errorf("Unexpected domains: got %v, expected %v", domains, expected)
+  }
+
+  values := d.GetBySuffix("example.com")
+  expected = []string{"example.com.", "Mail.Example.com", "www.example.com"}
+  if !testStringsEq(values, expected) {
+    t.Errorf("Unexpected values by suffix: got %v, expected %v", values, expected)
+  }
+
+  if !d.Delete("EXAMPLE.COM") || d.Contains("example.com") || !d.Contains("www.example.com") {
+    t.Errorf("Expected only 'example.com' to be deleted")
+  }
+}
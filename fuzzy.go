@@ -0,0 +1,171 @@
+package prefixmap
+
+import (
+  "sort"
+)
+
+// FuzzyMatch is a key found by a fuzzy search
+// along with its distance from the query
+type FuzzyMatch[V any] struct {
+  Prefix[V]
+
+  // The edit distance between the query and the key
+  Distance int
+}
+
+// FuzzyOption customizes the distance used by fuzzy searches
+type FuzzyOption func(*fuzzyOptions)
+
+type fuzzyOptions struct {
+  transpositions bool
+}
+
+// WithTranspositions makes fuzzy searches count the transposition
+// of two adjacent bytes as a single edit, as in the (restricted)
+// Damerau-Levenshtein distance.
+func WithTranspositions() FuzzyOption {
+  return func(o *fuzzyOptions) {
+    o.transpositions = true
+  }
+}
+
+// FuzzySearch returns the keys in the map within the given
+// Levenshtein distance from query, sorted by distance first
+// and lexicographically then.
+func (m *PrefixMap[V]) FuzzySearch(query string, maxDistance int, opts ...FuzzyOption) []FuzzyMatch[V] {
+  return m.fuzzy(query, maxDistance, false, opts)
+}
+
+// FuzzyPrefix returns the keys in the map starting with a prefix
+// within the given Levenshtein distance from query, sorted by distance
+// first and lexicographically then.
+// The distance of each match is the one of its closest prefix.
+func (m *PrefixMap[V]) FuzzyPrefix(query string, maxDistance int, opts ...FuzzyOption) []FuzzyMatch[V] {
+  return m.fuzzy(query, maxDistance, true, opts)
+}
+
+func (m *PrefixMap[V]) fuzzy(query string, maxDistance int, prefix bool, opts []FuzzyOption) []FuzzyMatch[V] {
+  query = m.opts.normalize(query)
+  s := &fuzzySearch[V]{
+    query:       query,
+    maxDistance: maxDistance,
+    prefix:      prefix,
+    matches:     []FuzzyMatch[V]{},
+  }
+  for _, opt := range opts {
+    opt(&s.fuzzyOptions)
+  }
+  if maxDistance < 0 {
+    return s.matches
+  }
+
+  // the first row is the distance of
+  // each query prefix from the empty key
+  row := make([]int, len(query)+1)
+  for j := range row {
+    row[j] = j
+  }
+  s.rows = [][]int{row}
+
+  mNode := (*Node[V])(m)
+  s.walk(mNode, len(query))
+  sort.SliceStable(s.matches, func(i, j int) bool {
+    return s.matches[i].Distance < s.matches[j].Distance
+  })
+
+  return s.matches
+}
+
+// fuzzySearch holds the state of a fuzzy search.
+// A row of the Levenshtein dynamic programming matrix is computed
+// for each byte of the keys walked so far: keys sharing a prefix
+// share its rows, which are computed only once.
+type fuzzySearch[V any] struct {
+  fuzzyOptions
+
+  query       string
+  maxDistance int
+  prefix      bool // true to match key prefixes against the query
+
+  rows    [][]int // rows[i] is the row for key[:i]
+  key     []byte
+  matches []FuzzyMatch[V]
+}
+
+// walk visits the subtree rooted at node, in lexicographic order.
+// best is the lowest distance between the query and the prefixes
+// of the key walked so far, only meaningful for prefix searches.
+// Branches whose rows exceed the maximum distance are skipped.
+func (s *fuzzySearch[V]) walk(node *Node[V], best int) {
+  depth := len(s.key)
+  defer func() {
+    s.key = s.key[:depth]
+  }()
+
+  for i := 0; i < len(node.key); i++ {
+    s.key = append(s.key, node.key[i])
+    rowMin := s.computeRow(len(s.key))
+    best = min(best, s.rows[len(s.key)][len(s.query)])
+
+    // no key down this branch can get any closer to the query
+    if rowMin > s.maxDistance {
+      if s.prefix && best <= s.maxDistance {
+        // but a prefix already matched: all keys here are matches
+        node.ascendNodes(append([]byte(nil), s.key[:depth]...), func(n *Node[V], key string) bool {
+          s.match(n, key, best)
+          return true
+        })
+      }
+      return
+    }
+  }
+
+  distance := s.rows[len(s.key)][len(s.query)]
+  if s.prefix {
+    distance = best
+  }
+  if node.isKey && distance <= s.maxDistance {
+    s.match(node, string(s.key), distance)
+  }
+
+  for _, c := range node.Children {
+    s.walk(c, best)
+  }
+}
+
+// computeRow computes the row for key[:depth]
+// from the previous ones and returns its minimum
+func (s *fuzzySearch[V]) computeRow(depth int) int {
+  if depth == len(s.rows) {
+    s.rows = append(s.rows, make([]int, len(s.query)+1))
+  }
+  row, prev := s.rows[depth], s.rows[depth-1]
+  c := s.key[depth-1]
+
+  row[0] = depth
+  rowMin := depth
+  for j := 1; j <= len(s.query); j++ {
+    cost := 1
+    if s.query[j-1] == c {
+      cost = 0
+    }
+    row[j] = min(prev[j]+1, row[j-1]+1, prev[j-1]+cost)
+    if s.transpositions && depth > 1 && j > 1 && c == s.query[j-2] && s.key[depth-2] == s.query[j-1] {
+      row[j] = min(row[j], s.rows[depth-2][j-2]+1)
+    }
+    rowMin = min(rowMin, row[j])
+  }
+
+  return rowMin
+}
+
+func (s *fuzzySearch[V]) match(node *Node[V], key string, distance int) {
+  s.matches = append(s.matches, FuzzyMatch[V]{
+    Prefix: Prefix[V]{
+      node:   node,
+      Key:    node.keyOf(key),
+      Values: node.data,
+    },
+    Distance: distance,
+  })
+}
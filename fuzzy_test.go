@@ -0,0 +1,142 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestFuzzySearch(t *testing.T) {
+  testCases := []struct {
+    query     string
+    distance  int
+    opts      []FuzzyOption
+    keys      []string
+    distances []int
+  }{
+    {
+      query:     "rubens",
+      distance:  0,
+      keys:      []string{"rubens"},
+      distances: []int{0},
+    },
+    {
+      query:     "rubes",
+      distance:  1,
+      keys:      []string{"rubens", "ruber"},
+      distances: []int{1, 1},
+    },
+    {
+      query:     "romanes",
+      distance:  2,
+      keys:      []string{"romane", "romanus"},
+      distances: []int{1, 1},
+    },
+    {
+      query:     "romanes",
+      distance:  3,
+      keys:      []string{"romane", "romanus", "romulus"},
+      distances: []int{1, 1, 3},
+    },
+    {
+      query:     "rubicno",
+      distance:  1,
+      keys:      []string{},
+      distances: []int{},
+    },
+    {
+      query:     "rubicno",
+      distance:  1,
+      opts:      []FuzzyOption{WithTranspositions()},
+      keys:      []string{"rubicon"},
+      distances: []int{1},
+    },
+    {
+      query:     "xyz",
+      distance:  2,
+      keys:      []string{},
+      distances: []int{},
+    },
+  }
+
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  for _, tc := range testCases {
+    matches := m.FuzzySearch(tc.query, tc.distance, tc.opts...)
+    keys, distances := []string{}, []int{}
+    for _, match := range matches {
+      keys = append(keys, match.Key)
+      distances = append(distances, match.Distance)
+    }
+    if !testStringsEq(keys, tc.keys) || !testIntsEq(distances, tc.distances) {
+      t.Errorf("Unexpected matches for '%s' within %d: got %v %v, expected %v %v", tc.query, tc.distance, keys, distances, tc.keys, tc.distances)
+    }
+  }
+}
+
+func TestFuzzyPrefix(t *testing.T) {
+  testCases := []struct {
+    query     string
+    distance  int
+    keys      []string
+    distances []int
+  }{
+    {
+      query:     "rubi",
+      distance:  0,
+      keys:      []string{"rubicon", "rubicundus"},
+      distances: []int{0, 0},
+    },
+    {
+      query:     "rubo",
+      distance:  1,
+      keys:      []string{"rubens", "ruber", "rubicon", "rubicundus"},
+      distances: []int{1, 1, 1, 1},
+    },
+    {
+      query:     "rubicn",
+      distance:  1,
+      keys:      []string{"rubicon", "rubicundus"},
+      distances: []int{1, 1},
+    },
+    {
+      query:     "a",
+      distance:  1,
+      keys:      []string{"A", "romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"},
+      distances: []int{1, 1, 1, 1, 1, 1, 1, 1},
+    },
+  }
+
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  for _, tc := range testCases {
+    matches := m.FuzzyPrefix(tc.query, tc.distance)
+    keys, distances := []string{}, []int{}
+    for _, match := range matches {
+      keys = append(keys, match.Key)
+      distances = append(distances, match.Distance)
+    }
+    if !testStringsEq(keys, tc.keys) || !testIntsEq(distances, tc.distances) {
+      t.Errorf("Unexpected matches for prefix '%s' within %d: got %v %v, expected %v %v", tc.query, tc.distance, keys, distances, tc.keys, tc.distances)
+    }
+  }
+}
+
+/* utils */
+func testIntsEq(a, b []int) bool {
+  if len(a) != len(b) {
+    return false
+  }
+
+  for i := range a {
+    if a[i] != b[i] {
+      return false
+    }
+  }
+
+  return true
+}
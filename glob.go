@@ -0,0 +1,272 @@
+package prefixmap
+
+import (
+  "errors"
+)
+
+// ErrBadPattern is returned by Match when the pattern is malformed
+var ErrBadPattern = errors.New("prefixmap: syntax error in pattern")
+
+// Match returns the keys in the map matching the given glob
+// pattern, in lexicographic order. The pattern syntax is:
+//
+//  '*'         matches any sequence of bytes
+//  '?'         matches any single byte
+//  '[' [ '!' | '^' ] { range } ']'
+//              matches any single byte within (or not within,
+//              if negated) the given ranges, as in '[a-c0-9_]'
+//  '\\' c      matches byte c
+//  c           matches byte c
+//
+// The pattern is matched against normalized keys, if the map
+// normalizes them.
+// Only the branches of the map that can still match the pattern
+// are visited: wildcards make the search explore several
+// branches at once.
+func (m *PrefixMap[V]) Match(pattern string) ([]Prefix[V], error) {
+  tokens, err := parseGlob(pattern)
+  if err != nil {
+    return nil, err
+  }
+
+  g := &globMatch[V]{
+    tokens:  tokens,
+    matches: []Prefix[V]{},
+  }
+  start := make([]bool, len(tokens)+1)
+  start[0] = true
+  g.closure(start)
+  g.states = [][]bool{start}
+
+  mNode := (*Node[V])(m)
+  g.walk(mNode)
+
+  return g.matches, nil
+}
+
+type globKind int
+
+const (
+  globLiteral globKind = iota
+  globAny
+  globStar
+  globClass
+)
+
+// globToken is a single element of a parsed glob pattern
+type globToken struct {
+  kind    globKind
+  b       byte      // the literal byte
+  ranges  [][2]byte // the class ranges, bounds included
+  negated bool      // true if the class is negated
+}
+
+// matches tells if the token consumes the given byte
+func (t *globToken) matches(c byte) bool {
+  switch t.kind {
+  case globLiteral:
+    return t.b == c
+  case globAny:
+    return true
+  case globClass:
+    in := false
+    for _, r := range t.ranges {
+      if r[0] <= c && c <= r[1] {
+        in = true
+        break
+      }
+    }
+    return in != t.negated
+  }
+
+  return false
+}
+
+func parseGlob(pattern string) ([]globToken, error) {
+  tokens := []globToken{}
+  for i := 0; i < len(pattern); i++ {
+    switch c := pattern[i]; c {
+    case '*':
+      // consecutive stars are equivalent to a single one
+      if len(tokens) == 0 || tokens[len(tokens)-1].kind != globStar {
+        tokens = append(tokens, globToken{kind: globStar})
+      }
+    case '?':
+      tokens = append(tokens, globToken{kind: globAny})
+    case '\\':
+      i++
+      if i == len(pattern) {
+        return nil, ErrBadPattern
+      }
+      tokens = append(tokens, globToken{kind: globLiteral, b: pattern[i]})
+    case '[':
+      token, n, err := parseGlobClass(pattern[i+1:])
+      if err != nil {
+        return nil, err
+      }
+      tokens = append(tokens, token)
+      i += n
+    default:
+      tokens = append(tokens, globToken{kind: globLiteral, b: c})
+    }
+  }
+
+  return tokens, nil
+}
+
+// parseGlobClass parses a character class whose opening
+// bracket precedes the given pattern and returns the number
+// of bytes parsed, closing bracket included
+func parseGlobClass(pattern string) (globToken, int, error) {
+  token := globToken{kind: globClass}
+  i := 0
+  if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+    token.negated = true
+    i++
+  }
+
+  // reads a possibly escaped class byte
+  next := func() (byte, error) {
+    if i < len(pattern) && pattern[i] == '\\' {
+      i++
+    }
+    if i == len(pattern) {
+      return 0, ErrBadPattern
+    }
+    i++
+    return pattern[i-1], nil
+  }
+
+  for {
+    if i == len(pattern) {
+      return token, 0, ErrBadPattern
+    }
+    if pattern[i] == ']' {
+      if len(token.ranges) == 0 {
+        return token, 0, ErrBadPattern
+      }
+      return token, i + 1, nil
+    }
+
+    lo, err := next()
+    if err != nil {
+      return token, 0, err
+    }
+    hi := lo
+    if i+1 < len(pattern) && pattern[i] == '-' && pattern[i+1] != ']' {
+      i++
+      if hi, err = next(); err != nil {
+        return token, 0, err
+      }
+      if hi < lo {
+        return token, 0, ErrBadPattern
+      }
+    }
+    token.ranges = append(token.ranges, [2]byte{lo, hi})
+  }
+}
+
+// globMatch holds the state of a glob search.
+// The pattern is evaluated as a nondeterministic automaton
+// whose states are the positions within the pattern tokens,
+// the last one being the accepting state.
+type globMatch[V any] struct {
+  tokens  []globToken
+  states  [][]bool // states[i] is the set of states for key[:i]
+  key     []byte
+  matches []Prefix[V]
+}
+
+// walk visits the subtree rooted at node, skipping
+// the branches the pattern cannot match anymore
+func (g *globMatch[V]) walk(node *Node[V]) {
+  depth := len(g.key)
+  defer func() {
+    g.key = g.key[:depth]
+  }()
+
+  for i := 0; i < len(node.key); i++ {
+    g.key = append(g.key, node.key[i])
+    if !g.step(len(g.key)) {
+      return
+    }
+  }
+
+  states := g.states[len(g.key)]
+  if g.matchesAll(states) {
+    node.ascendNodes(append([]byte(nil), g.key[:depth]...), func(n *Node[V], key string) bool {
+      g.match(n, key)
+      return true
+    })
+    return
+  }
+  if node.isKey && states[len(g.tokens)] {
+    g.match(node, string(g.key))
+  }
+
+  for _, c := range node.Children {
+    g.walk(c)
+  }
+}
+
+// step computes the states for key[:depth] by feeding the
+// last key byte to the previous states.
+// Returns false if no state is left.
+func (g *globMatch[V]) step(depth int) bool {
+  if depth == len(g.states) {
+    g.states = append(g.states, make([]bool, len(g.tokens)+1))
+  }
+  prev, next := g.states[depth-1], g.states[depth]
+  c := g.key[depth-1]
+
+  alive := false
+  for i := range next {
+    next[i] = false
+  }
+  for i, t := range g.tokens {
+    if !prev[i] {
+      continue
+    }
+    if t.kind == globStar {
+      next[i] = true
+      alive = true
+    } else if t.matches(c) {
+      next[i+1] = true
+      alive = true
+    }
+  }
+  g.closure(next)
+
+  return alive
+}
+
+// closure adds to the given states the ones
+// reachable by skipping stars
+func (g *globMatch[V]) closure(states []bool) {
+  for i, t := range g.tokens {
+    if states[i] && t.kind == globStar {
+      states[i+1] = true
+    }
+  }
+}
+
+// matchesAll tells if any key continuing the current
+// one matches the pattern: this happens when a state
+// is only followed by stars.
+func (g *globMatch[V]) matchesAll(states []bool) bool {
+  for i := len(g.tokens) - 1; i >= 0 && g.tokens[i].kind == globStar; i-- {
+    if states[i] {
+      return true
+    }
+  }
+
+  return false
+}
+
+func (g *globMatch[V]) match(node *Node[V], key string) {
+  g.matches = append(g.matches, Prefix[V]{
+    node:   node,
+    Key:    node.keyOf(key),
+    Values: node.data,
+  })
+}
@@ -0,0 +1,77 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestMatch(t *testing.T) {
+  keys := []string{
+    "user:1:session",
+    "user:1:profile",
+    "user:22:session",
+    "user:3:settings",
+    "users",
+    "admin:1:session",
+  }
+
+  testCases := []struct {
+    pattern  string
+    expected []string
+  }{
+    {"user:*:session", []string{"user:1:session", "user:22:session"}},
+    {"user:?:*", []string{"user:1:profile", "user:1:session", "user:3:settings"}},
+    {"user:[2-3]*", []string{"user:22:session", "user:3:settings"}},
+    {"user:[!2-3]*", []string{"user:1:profile", "user:1:session"}},
+    {"*:1:*", []string{"admin:1:session", "user:1:profile", "user:1:session"}},
+    {"user?", []string{"users"}},
+    {"user", []string{}},
+    {"users", []string{"users"}},
+    {"*s", []string{"user:3:settings", "users"}},
+    {"*", keys},
+    {"user:1:session\\*", []string{}},
+  }
+
+  m := New()
+  for _, key := range keys {
+    m.Insert(key, key)
+  }
+  expectedAll := []string{}
+  m.Ascend(func(key string, _ []interface{}) bool {
+    expectedAll = append(expectedAll, key)
+    return true
+  })
+
+  for _, tc := range testCases {
+    matches, err := m.Match(tc.pattern)
+    if err != nil {
+      t.Errorf("Unexpected error for pattern '%s': %v", tc.pattern, err)
+      continue
+    }
+
+    expected := tc.expected
+    if tc.pattern == "*" {
+      expected = expectedAll
+    }
+    found := []string{}
+    for _, p := range matches {
+      if testEq(p.Values, []interface{}{p.Key}) != true {
+        t.Errorf("Unexpected values for key '%s': got %v", p.Key, p.Values)
+      }
+      found = append(found, p.Key)
+    }
+    if !testStringsEq(found, expected) {
+      t.Errorf("Unexpected matches for pattern '%s': got %v, expected %v", tc.pattern, found, expected)
+    }
+  }
+}
+
+func TestMatchBadPattern(t *testing.T) {
+  m := New()
+  m.Insert("key", "key")
+
+  for _, pattern := range []string{"[", "[]", "[a-", "[c-a]", "key\\", "[!]"} {
+    if _, err := m.Match(pattern); err != ErrBadPattern {
+      t.Errorf("Unexpected error for pattern '%s': got %v, expected %v", pattern, err, ErrBadPattern)
+    }
+  }
+}
@@ -0,0 +1,5 @@
+module github.com/alediaferia/prefixmap
+
+go 1.23.0
+
+require golang.org/x/text v0.28.0
@@ -0,0 +1,42 @@
+package prefixmap
+
+import (
+  "iter"
+)
+
+// All returns an iterator over the keys in the map and their
+// values, in ascending lexicographic order.
+// Nodes not holding a key are never yielded.
+func (m *PrefixMap[V]) All() iter.Seq2[string, []V] {
+  return func(yield func(string, []V) bool) {
+    m.Ascend(yield)
+  }
+}
+
+// Keys returns an iterator over the keys
+// in the map, in ascending lexicographic order
+func (m *PrefixMap[V]) Keys() iter.Seq[string] {
+  return func(yield func(string) bool) {
+    m.Ascend(func(key string, _ []V) bool {
+      return yield(key)
+    })
+  }
+}
+
+// Values returns an iterator over the values associated
+// with each key in the map, in ascending key order
+func (m *PrefixMap[V]) Values() iter.Seq[[]V] {
+  return func(yield func([]V) bool) {
+    m.Ascend(func(_ string, values []V) bool {
+      return yield(values)
+    })
+  }
+}
+
+// WithPrefix returns an iterator over the keys starting with
+// the given prefix and their values, in ascending lexicographic order
+func (m *PrefixMap[V]) WithPrefix(prefix string) iter.Seq2[string, []V] {
+  return func(yield func(string, []V) bool) {
+    m.AscendPrefix(prefix, yield)
+  }
+}
@@ -0,0 +1,80 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestAll(t *testing.T) {
+  m := NewOf[int]()
+  m.Insert("romane", 1)
+  m.Insert("romanus", 2)
+  m.Insert("romulus", 3, 4)
+
+  keys := []string{}
+  sum := 0
+  for key, values := range m.All() {
+    keys = append(keys, key)
+    for _, v := range values {
+      sum += v
+    }
+  }
+  // 'roman' and 'rom' are split nodes and never yielded
+  if expected := []string{"romane", "romanus", "romulus"}; !testStringsEq(keys, expected) {
+    t.Errorf("Unexpected keys: got %v, expected %v", keys, expected)
+  }
+  if sum != 10 {
+    t.Errorf("Unexpected sum of values: got %d, expected %d", sum, 10)
+  }
+}
+
+func TestKeysValuesBreak(t *testing.T) {
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  keys := []string{}
+  for key := range m.Keys() {
+    if key == "romulus" {
+      break
+    }
+    keys = append(keys, key)
+  }
+  if expected := []string{"A", "romane", "romanus"}; !testStringsEq(keys, expected) {
+    t.Errorf("Unexpected keys: got %v, expected %v", keys, expected)
+  }
+
+  count := 0
+  for values := range m.Values() {
+    if testEq(values, []interface{}{"A"}) != true {
+      t.Errorf("Unexpected first values: got %v", values)
+    }
+    count++
+    break
+  }
+  if count != 1 {
+    t.Errorf("Unexpected number of iterations: got %d, expected %d", count, 1)
+  }
+}
+
+func TestWithPrefix(t *testing.T) {
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  keys := []string{}
+  for key, values := range m.WithPrefix("rubi") {
+    if testEq(values, []interface{}{key}) != true {
+      t.Errorf("Unexpected values for key '%s': got %v", key, values)
+    }
+    keys = append(keys, key)
+  }
+  if expected := []string{"rubicon", "rubicundus"}; !testStringsEq(keys, expected) {
+    t.Errorf("Unexpected keys with prefix 'rubi': got %v, expected %v", keys, expected)
+  }
+
+  for range m.WithPrefix("x") {
+    t.Errorf("No key is expected to start with 'x'")
+  }
+}
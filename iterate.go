@@ -0,0 +1,103 @@
+package prefixmap
+
+// KeyCallback is invoked by the ordered iteration methods
+// (Ascend, Descend and the like) for each key reached.
+// Returning false stops the iteration.
+type KeyCallback[V any] func(key string, values []V) bool
+
+// Ascend iterates over the keys contained in the map
+// in ascending lexicographic order
+func (m *PrefixMap[V]) Ascend(callback KeyCallback[V]) {
+  mNode := (*Node[V])(m)
+  mNode.ascend(nil, callback)
+}
+
+// Descend iterates over the keys contained in the map
+// in descending lexicographic order
+func (m *PrefixMap[V]) Descend(callback KeyCallback[V]) {
+  mNode := (*Node[V])(m)
+  mNode.descend(nil, callback)
+}
+
+// AscendPrefix iterates over the keys starting with the given
+// prefix in ascending lexicographic order
+func (m *PrefixMap[V]) AscendPrefix(prefix string, callback KeyCallback[V]) {
+  mNode := (*Node[V])(m)
+  n := mNode.nodeForPrefix(m.opts.normalize(prefix))
+  if n == nil {
+    return
+  }
+  n.ascend(n.parentKey(), callback)
+}
+
+// DescendPrefix iterates over the keys starting with the given
+// prefix in descending lexicographic order
+func (m *PrefixMap[V]) DescendPrefix(prefix string, callback KeyCallback[V]) {
+  mNode := (*Node[V])(m)
+  n := mNode.nodeForPrefix(m.opts.normalize(prefix))
+  if n == nil {
+    return
+  }
+  n.descend(n.parentKey(), callback)
+}
+
+// parentKey returns the key of the parent
+// of m, nil for the root
+func (m *Node[V]) parentKey() []byte {
+  if m.Parent == nil {
+    return nil
+  }
+  return []byte(m.Parent.Key())
+}
+
+// nodeCallback is invoked by the subtree walks for
+// each node holding a key, along with its normalized key
+type nodeCallback[V any] func(node *Node[V], key string) bool
+
+// ascend visits the subtree rooted at m in ascending key order.
+// key holds the key of the parent of m.
+// Returns false if the callback halted the iteration.
+func (m *Node[V]) ascend(key []byte, callback KeyCallback[V]) bool {
+  return m.ascendNodes(key, func(node *Node[V], key string) bool {
+    return callback(node.keyOf(key), node.data)
+  })
+}
+
+// descend visits the subtree rooted at m in descending key order.
+// key holds the key of the parent of m.
+// Returns false if the callback halted the iteration.
+func (m *Node[V]) descend(key []byte, callback KeyCallback[V]) bool {
+  return m.descendNodes(key, func(node *Node[V], key string) bool {
+    return callback(node.keyOf(key), node.data)
+  })
+}
+
+// ascendNodes visits the subtree rooted at m in pre-order:
+// since children are sorted by key this yields the key
+// nodes in ascending order.
+func (m *Node[V]) ascendNodes(key []byte, callback nodeCallback[V]) bool {
+  key = append(key, m.key...)
+  if m.isKey && !callback(m, string(key)) {
+    return false
+  }
+  for _, c := range m.Children {
+    if !c.ascendNodes(key, callback) {
+      return false
+    }
+  }
+
+  return true
+}
+
+// descendNodes visits the subtree rooted at m in reverse
+// post-order, yielding the key nodes in descending order.
+func (m *Node[V]) descendNodes(key []byte, callback nodeCallback[V]) bool {
+  key = append(key, m.key...)
+  for i := len(m.Children) - 1; i >= 0; i-- {
+    if !m.Children[i].descendNodes(key, callback) {
+      return false
+    }
+  }
+
+  return !m.isKey || callback(m, string(key))
+}
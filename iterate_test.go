@@ -0,0 +1,121 @@
+package prefixmap
+
+import (
+  "sort"
+  "testing"
+)
+
+func TestAscendDescend(t *testing.T) {
+  for _, v := range nodeTests {
+    m := New()
+    // inserting in reverse order
+    for i := len(v.words) - 1; i >= 0; i-- {
+      m.Insert(v.words[i], v.words[i])
+    }
+
+    expected := append([]string{}, v.words...)
+    sort.Strings(expected)
+
+    keys := []string{}
+    m.Ascend(func(key string, values []interface{}) bool {
+      if testEq(values, []interface{}{key}) != true {
+        t.Errorf("Unexpected values for key '%s': got %v", key, values)
+      }
+      keys = append(keys, key)
+      return true
+    })
+    if !testStringsEq(keys, expected) {
+      t.Errorf("Unexpected ascending keys: got %v, expected %v", keys, expected)
+    }
+
+    sort.Sort(sort.Reverse(sort.StringSlice(expected)))
+    keys = []string{}
+    m.Descend(func(key string, values []interface{}) bool {
+      keys = append(keys, key)
+      return true
+    })
+    if !testStringsEq(keys, expected) {
+      t.Errorf("Unexpected descending keys: got %v, expected %v", keys, expected)
+    }
+  }
+}
+
+func TestAscendDescendPrefix(t *testing.T) {
+  testCases := []struct {
+    prefix     string
+    ascending  []string
+    descending []string
+  }{
+    {
+      prefix:     "rub",
+      ascending:  []string{"rubens", "ruber", "rubicon", "rubicundus"},
+      descending: []string{"rubicundus", "rubicon", "ruber", "rubens"},
+    },
+    {
+      prefix:     "rubico",
+      ascending:  []string{"rubicon"},
+      descending: []string{"rubicon"},
+    },
+    {
+      prefix:     "rubx",
+      ascending:  []string{},
+      descending: []string{},
+    },
+  }
+
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  for _, tc := range testCases {
+    keys := []string{}
+    m.AscendPrefix(tc.prefix, func(key string, values []interface{}) bool {
+      keys = append(keys, key)
+      return true
+    })
+    if !testStringsEq(keys, tc.ascending) {
+      t.Errorf("Unexpected ascending keys for prefix '%s': got %v, expected %v", tc.prefix, keys, tc.ascending)
+    }
+
+    keys = []string{}
+    m.DescendPrefix(tc.prefix, func(key string, values []interface{}) bool {
+      keys = append(keys, key)
+      return true
+    })
+    if !testStringsEq(keys, tc.descending) {
+      t.Errorf("Unexpected descending keys for prefix '%s': got %v, expected %v", tc.prefix, keys, tc.descending)
+    }
+  }
+}
+
+func TestAscendHalts(t *testing.T) {
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  keys := []string{}
+  m.Ascend(func(key string, values []interface{}) bool {
+    keys = append(keys, key)
+    return len(keys) < 2
+  })
+  if expected := []string{"A", "romane"}; !testStringsEq(keys, expected) {
+    t.Errorf("Unexpected keys: got %v, expected %v", keys, expected)
+  }
+}
+
+/* utils */
+func testStringsEq(a, b []string) bool {
+  if len(a) != len(b) {
+    return false
+  }
+
+  for i := range a {
+    if a[i] != b[i] {
+      return false
+    }
+  }
+
+  return true
+}
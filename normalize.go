@@ -0,0 +1,154 @@
+package prefixmap
+
+import (
+  "strings"
+  "unicode"
+  "unicode/utf8"
+
+  "golang.org/x/text/unicode/norm"
+)
+
+// KeyNormalizer maps a key to its normalized form.
+// Maps created with a normalizer store and look up keys by their
+// normalized form, so that keys normalizing the same way refer
+// to the same entry, but still report each key spelled as it
+// was first inserted.
+//
+// Normalizers are applied to whole keys as well as to prefixes:
+// they are expected to map each prefix of a key to a prefix
+// of the normalized key, as the built-in ones do, NFC aside
+// for prefixes ending within a combining sequence.
+type KeyNormalizer func(key string) string
+
+// WithNormalizer makes the map normalize keys with the given
+// normalizer before storing or looking them up
+func WithNormalizer(normalizer KeyNormalizer) Option {
+  return func(o *options) {
+    o.normalizer = normalizer
+  }
+}
+
+// ChainNormalizers returns a normalizer applying
+// the given normalizers in order
+func ChainNormalizers(normalizers ...KeyNormalizer) KeyNormalizer {
+  return func(key string) string {
+    for _, n := range normalizers {
+      key = n(key)
+    }
+    return key
+  }
+}
+
+// ASCIIFold maps the ASCII upper case letters
+// in the key to lower case, leaving the rest untouched
+func ASCIIFold(key string) string {
+  for i := 0; i < len(key); i++ {
+    if 'A' <= key[i] && key[i] <= 'Z' {
+      b := []byte(key)
+      for ; i < len(b); i++ {
+        if 'A' <= b[i] && b[i] <= 'Z' {
+          b[i] += 'a' - 'A'
+        }
+      }
+      return string(b)
+    }
+  }
+
+  return key
+}
+
+// UnicodeFold applies Unicode simple case folding to the key:
+// the runes equivalent under simple case folding, as enumerated by
+// unicode.SimpleFold, share the same form (e.g. 'S', 's' and 'ſ'
+// all become 's'), while the runes only related by special casing
+// rules are kept apart (e.g. 'ı' and 'İ' are not folded to 'i').
+func UnicodeFold(key string) string {
+  return strings.Map(foldRune, key)
+}
+
+// foldRune returns the canonical rune of the simple case folding
+// orbit of r: the lower case of its smallest rune having a lower
+// case in the orbit, if any, or else the smallest rune of the orbit
+func foldRune(r rune) rune {
+  if r < utf8.RuneSelf {
+    if 'A' <= r && r <= 'Z' {
+      r += 'a' - 'A'
+    }
+    return r
+  }
+
+  // orbits hold at most 4 runes
+  orbit := [4]rune{r}
+  n := 1
+  for f := unicode.SimpleFold(r); f != r && n < len(orbit); f = unicode.SimpleFold(f) {
+    orbit[n] = f
+    n++
+  }
+
+  canonical, smallest := rune(-1), r
+  for _, f := range orbit[:n] {
+    smallest = min(smallest, f)
+    if lower := unicode.ToLower(f); lower != f && (canonical < 0 || f < canonical) {
+      for _, g := range orbit[:n] {
+        if g == lower {
+          canonical = f
+        }
+      }
+    }
+  }
+  if canonical < 0 {
+    return smallest
+  }
+  return unicode.ToLower(canonical)
+}
+
+// NFC maps the key to its Unicode canonical composition (NFC), so
+// that the precomposed and the decomposed spellings of a key share
+// the same form (e.g. "café" and "cafe\u0301").
+// A prefix ending between a letter and its combining marks composes
+// differently from the whole key: it is not a prefix of the key.
+func NFC(key string) string {
+  return norm.NFC.String(key)
+}
+
+// StripDiacritics removes the diacritical marks from the key:
+// combining marks are dropped and the precomposed Latin letters
+// are mapped to their base letter (e.g. "Zürich" becomes "Zurich").
+func StripDiacritics(key string) string {
+  ascii := true
+  for i := 0; i < len(key); i++ {
+    if key[i] >= utf8.RuneSelf {
+      ascii = false
+      break
+    }
+  }
+  if ascii {
+    return key
+  }
+
+  return strings.Map(func(r rune) rune {
+    if unicode.Is(unicode.Mn, r) {
+      return -1
+    }
+    if r >= latinBaseStart && r < latinBaseStart+rune(len(latinBase)) {
+      if base := latinBase[r-latinBaseStart]; base != '.' {
+        return rune(base)
+      }
+    }
+    return r
+  }, key)
+}
+
+// latinBaseStart is the first rune in latinBase
+const latinBaseStart = 0xC0
+
+// latinBase holds the base letter of each rune from U+00C0
+// to U+024F, '.' standing for runes having no base letter
+const latinBase = "" +
+  "AAAAAA.CEEEEIIIIDNOOOOO.OUUUUY..aaaaaa.ceeeeiiii.nooooo.ouuuuy.y" +
+  "AaAaAaCcCcCcCcDdDdEeEeEeEeEeGgGgGgGgHhHhIiIiIiIiIi..JjKk.LlLlLl." +
+  ".LlNnNnNn...OoOoOo..RrRrRrSsSsSsSsTtTtTtUuUuUuUuUuUuWwYyYZzZzZz." +
+  "................................Oo.............Uu..............." +
+  ".............AaIiOoUuUuUuUuUu.AaAa....GgKkOoOo..j...Gg..NnAa...." +
+  "AaAaEeEeIiIiOoOoRrRrUuUuSsTt..Hh......AaEeOoOoOoOoYy............" +
+  "................"
@@ -0,0 +1,98 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestNormalizers(t *testing.T) {
+  testCases := []struct {
+    normalizer KeyNormalizer
+    key        string
+    expected   string
+  }{
+    {ASCIIFold, "ZURICH", "zurich"},
+    {ASCIIFold, "Zürich", "zürich"},
+    {ASCIIFold, "zurich", "zurich"},
+    {UnicodeFold, "ZÜRICH", "zürich"},
+    {UnicodeFold, "ΣΊΣΥΦΟΣ", "σίσυφοσ"},
+    {UnicodeFold, "ſ", "s"},
+    {UnicodeFold, "\u212A", "k"}, // Kelvin sign
+    {UnicodeFold, "ǅ", "ǆ"},
+    {UnicodeFold, "ς", "σ"},
+    {UnicodeFold, "ı", "ı"},
+    {UnicodeFold, "İ", "İ"},
+    {UnicodeFold, "Iı", "iı"},
+    {UnicodeFold, "日本", "日本"},
+    {NFC, "cafe\u0301", "café"},
+    {NFC, "café", "café"},
+    {NFC, "Zu\u0308rich", "Zürich"},
+    {StripDiacritics, "Zürich", "Zurich"},
+    {StripDiacritics, "Zu\u0308rich", "Zurich"},
+    {StripDiacritics, "Łódź", "Lodz"},
+    {StripDiacritics, "日本", "日本"},
+    {ChainNormalizers(StripDiacritics, UnicodeFold), "ZÜRICH", "zurich"},
+  }
+
+  for _, tc := range testCases {
+    if normalized := tc.normalizer(tc.key); normalized != tc.expected {
+      t.Errorf("Unexpected normalized key for '%s': got '%s', expected '%s'", tc.key, normalized, tc.expected)
+    }
+  }
+}
+
+func TestNormalizedMap(t *testing.T) {
+  m := New(WithNormalizer(ChainNormalizers(StripDiacritics, UnicodeFold)))
+  m.Insert("Zürich", 1)
+  m.Insert("zurich", 2)
+  m.Insert("ZURICH", 3)
+  m.Insert("Zug", 4)
+
+  if l := m.Len(); l != 2 {
+    t.Errorf("Unexpected length: got %d, expected %d", l, 2)
+  }
+  if data := m.Get("zürich"); testEq(data, []interface{}{1, 2, 3}) != true {
+    t.Errorf("Unexpected values for key 'zürich': got %v", data)
+  }
+  if !m.Contains("ZÜRICH") || !m.ContainsPrefix("ZÜ") {
+    t.Errorf("Key 'ZÜRICH' is expected to be found")
+  }
+  if data := m.GetByPrefix("ZU"); testEq(data, []interface{}{4, 1, 2, 3}) != true {
+    t.Errorf("Unexpected values for prefix 'ZU': got %v", data)
+  }
+
+  // iterating returns the keys as they were first inserted
+  keys := []string{}
+  for key := range m.Keys() {
+    keys = append(keys, key)
+  }
+  if expected := []string{"Zug", "Zürich"}; !testStringsEq(keys, expected) {
+    t.Errorf("Unexpected keys: got %v, expected %v", keys, expected)
+  }
+
+  if key, _, ok := m.LongestPrefix("ZURICH/HB"); !ok || key != "Zürich" {
+    t.Errorf("Unexpected longest prefix: got ('%s', %v)", key, ok)
+  }
+  if key, _, ok := m.Floor("zz"); !ok || key != "Zürich" {
+    t.Errorf("Unexpected floor: got ('%s', %v)", key, ok)
+  }
+
+  if !m.Delete("ZURICH") || m.Contains("Zürich") {
+    t.Errorf("Key 'Zürich' is expected to be deleted")
+  }
+}
+
+func TestNFCMap(t *testing.T) {
+  m := NewOf[int](WithNormalizer(ChainNormalizers(NFC, UnicodeFold)))
+  m.Insert("café", 1)
+  m.Insert("CAFE\u0301", 2)
+
+  if l := m.Len(); l != 1 {
+    t.Errorf("Unexpected length: got %d, expected %d", l, 1)
+  }
+  if !m.Contains("cafe\u0301") || !m.ContainsPrefix("cafe\u0301") {
+    t.Errorf("Key 'cafe\\u0301' is expected to be found")
+  }
+  if data := m.Get("Café"); !testIntsEq(data, []int{1, 2}) {
+    t.Errorf("Unexpected values for key 'Café': got %v", data)
+  }
+}
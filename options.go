@@ -0,0 +1,60 @@
+package prefixmap
+
+import (
+  "unicode/utf8"
+)
+
+// Option configures a map created by New or NewOf
+type Option func(*options)
+
+type options struct {
+  runes      bool          // true to split keys on rune boundaries only
+  normalizer KeyNormalizer // applied to keys, if any
+}
+
+// RuneAware makes the map treat keys as UTF-8 strings:
+// nodes are only split on rune boundaries, so that a multi-byte
+// rune is never spread across nodes and every prefix reported
+// by EachPrefix is valid UTF-8 as long as the keys are.
+//
+// Prefix lookups (GetByPrefix, CountPrefix, DeletePrefix and the
+// like) compare prefixes rune by rune: a prefix ending with an
+// incomplete rune matches no key.
+func RuneAware() Option {
+  return func(o *options) {
+    o.runes = true
+  }
+}
+
+// lcpIndex is like the lcpIndex function but, in
+// rune-aware mode, never splits a rune
+func (o *options) lcpIndex(a, b string) int {
+  l := lcpIndex(a, b) + 1
+  if o == nil || !o.runes {
+    return l - 1
+  }
+  for l > 0 && (l < len(a) && !utf8.RuneStart(a[l]) || l < len(b) && !utf8.RuneStart(b[l])) {
+    l--
+  }
+
+  return l - 1
+}
+
+// firstLen returns the length of the first unit of s:
+// a byte or, in rune-aware mode, a rune
+func (o *options) firstLen(s string) int {
+  if o == nil || !o.runes {
+    return 1
+  }
+  _, size := utf8.DecodeRuneInString(s)
+  return size
+}
+
+// normalize returns the normalized form of the given
+// key, or the key itself if the map doesn't normalize keys
+func (o *options) normalize(key string) string {
+  if o == nil || o.normalizer == nil {
+    return key
+  }
+  return o.normalizer(key)
+}
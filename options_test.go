@@ -0,0 +1,112 @@
+package prefixmap
+
+import (
+  "testing"
+  "unicode/utf8"
+)
+
+func TestRuneAware(t *testing.T) {
+  keys := []string{"café", "cafè", "caffè", "日本", "日本語", "日曜"}
+
+  m := New(RuneAware())
+  for _, key := range keys {
+    m.Insert(key, key)
+  }
+
+  for _, key := range keys {
+    if data := m.Get(key); testEq(data, []interface{}{key}) != true {
+      t.Errorf("Unexpected value for key '%s': expected (%v), got (%v)", key, []interface{}{key}, data)
+    }
+  }
+
+  m.EachPrefix(func(prefix Prefix[any]) (bool, bool) {
+    if !utf8.ValidString(prefix.Key) {
+      t.Errorf("Invalid UTF-8 prefix: %q", prefix.Key)
+    }
+    return false, false
+  })
+
+  testCases := []struct {
+    prefix string
+    count  int
+  }{
+    {"caf", 3},
+    {"café", 1},
+    {"caf\xc3", 0},
+    {"日", 3},
+    {"日本", 2},
+    {"\xe6\x97", 0},
+  }
+  for _, tc := range testCases {
+    if count := m.CountPrefix(tc.prefix); count != tc.count {
+      t.Errorf("Unexpected count for prefix %q: got %d, expected %d", tc.prefix, count, tc.count)
+    }
+    if values := m.GetByPrefix(tc.prefix); len(values) != tc.count {
+      t.Errorf("Unexpected values for prefix %q: got %v", tc.prefix, values)
+    }
+  }
+
+  m.Delete("café")
+  m.Delete("日曜")
+  if count := (*Node[any])(m).countNodes(); count != 6 {
+    (*Node[any])(m).print(-1)
+    t.Errorf("Unexpected node count after deletion: got %d, expected %d", count, 6)
+  }
+}
+
+func TestByteSplits(t *testing.T) {
+  m := New()
+  m.Insert("café", "café")
+  m.Insert("cafè", "cafè")
+
+  invalid := 0
+  m.EachPrefix(func(prefix Prefix[any]) (bool, bool) {
+    if !utf8.ValidString(prefix.Key) {
+      invalid++
+    }
+    return false, false
+  })
+  if invalid != 1 {
+    t.Errorf("Unexpected number of invalid UTF-8 prefixes: got %d, expected %d", invalid, 1)
+  }
+  if count := m.CountPrefix("caf\xc3"); count != 2 {
+    t.Errorf("Unexpected count for a partial rune prefix: got %d, expected %d", count, 2)
+  }
+}
+
+func TestRuneAwareCursor(t *testing.T) {
+  m := New(RuneAware())
+  for _, key := range []string{"café", "cafè", "caffè"} {
+    m.Insert(key, key)
+  }
+
+  c := m.Cursor()
+  if !c.AdvanceString("caf\xc3") {
+    t.Fatalf("Cannot advance cursor to a partial rune")
+  }
+  if c.IsKey() || c.Key() != "caf\xc3" {
+    t.Errorf("Unexpected cursor state: key %q, isKey %v", c.Key(), c.IsKey())
+  }
+  if children := string(c.Children()); children != "\xa8\xa9" {
+    t.Errorf("Unexpected cursor children: got %q, expected %q", children, "\xa8\xa9")
+  }
+  if completions := c.Completions(0); len(completions) != 2 {
+    t.Errorf("Unexpected completions: got %v", completions)
+  }
+
+  if !c.Advance(0xa8) || !c.IsKey() || c.Key() != "cafè" {
+    t.Errorf("Unexpected cursor state: key %q, isKey %v", c.Key(), c.IsKey())
+  }
+  if !c.Back() || !c.Advance(0xa9) || !c.IsKey() || c.Key() != "café" {
+    t.Errorf("Unexpected cursor state: key %q, isKey %v", c.Key(), c.IsKey())
+  }
+  if c.Advance('x') {
+    t.Errorf("Cursor is not expected to advance by 'x'")
+  }
+
+  for c.Back() {
+  }
+  if !c.AdvanceString("caffè") || !c.IsKey() {
+    t.Errorf("Cannot advance cursor to 'caffè'")
+  }
+}
@@ -0,0 +1,371 @@
+package prefixmap
+
+import (
+  "iter"
+  "sort"
+  "strings"
+  "sync/atomic"
+)
+
+// ImmutablePrefixMap is a prefix map which is never modified once
+// built: Insert, Replace and Delete return a new map sharing all the
+// nodes untouched by the change with the original one, the nodes
+// along the path to the key being copied instead of modified in place.
+// An ImmutablePrefixMap is safe for concurrent use and the zero
+// value is an empty map.
+type ImmutablePrefixMap[V any] struct {
+  root *immutableNode[V]
+  len  int
+}
+
+// immutableNode is a node of an ImmutablePrefixMap. As nodes are
+// shared among maps, a node has no parent pointer.
+type immutableNode[V any] struct {
+  key      string
+  children []*immutableNode[V] // sorted by key
+  isKey    bool
+  data     []V
+}
+
+// NewImmutable returns a new empty ImmutablePrefixMap
+func NewImmutable[V any]() *ImmutablePrefixMap[V] {
+  return &ImmutablePrefixMap[V]{}
+}
+
+// Insert returns a map with new values for the specified key,
+// appended to the existing ones if any
+func (m *ImmutablePrefixMap[V]) Insert(key string, values ...V) *ImmutablePrefixMap[V] {
+  return m.set(key, func(data []V) []V {
+    // copying the data shared with the original map
+    return append(data[:len(data):len(data)], values...)
+  })
+}
+
+// Replace returns a map with the value(s) for the
+// given key replaced with the given ones
+func (m *ImmutablePrefixMap[V]) Replace(key string, values ...V) *ImmutablePrefixMap[V] {
+  return m.set(key, func([]V) []V {
+    return values
+  })
+}
+
+// Delete returns a map without the given key and its values.
+// Returns the map itself and false if no such key is present.
+func (m *ImmutablePrefixMap[V]) Delete(key string) (*ImmutablePrefixMap[V], bool) {
+  if m.root == nil {
+    return m, false
+  }
+
+  root, ok := m.root.delete(key)
+  if !ok {
+    return m, false
+  }
+  if root == nil {
+    root = &immutableNode[V]{}
+  } else if root.key != "" {
+    // the root keeps the empty key: undoing the compaction
+    root = &immutableNode[V]{children: []*immutableNode[V]{root}}
+  }
+
+  return &ImmutablePrefixMap[V]{root: root, len: m.len - 1}, true
+}
+
+// Len returns the number of keys in the map
+func (m *ImmutablePrefixMap[V]) Len() int {
+  return m.len
+}
+
+// Contains checks if the given key is present in the map
+func (m *ImmutablePrefixMap[V]) Contains(key string) bool {
+  node := m.nodeForKey(key)
+  return node != nil && node.isKey
+}
+
+// Get returns the data associated with the given key in the map
+// or nil if no such key is present in the map
+func (m *ImmutablePrefixMap[V]) Get(key string) []V {
+  node := m.nodeForKey(key)
+  if node == nil || !node.isKey {
+    return nil
+  }
+  return node.data
+}
+
+// GetByPrefix returns a flattened collection of values
+// associated with the given prefix key, sorted by key
+func (m *ImmutablePrefixMap[V]) GetByPrefix(prefix string) []V {
+  values := []V{}
+  for _, v := range m.WithPrefix(prefix) {
+    values = append(values, v...)
+  }
+  return values
+}
+
+// All returns an iterator over the keys in the map and
+// their values, in ascending lexicographic order
+func (m *ImmutablePrefixMap[V]) All() iter.Seq2[string, []V] {
+  return m.WithPrefix("")
+}
+
+// WithPrefix returns an iterator over the keys starting with
+// the given prefix and their values, in ascending lexicographic order
+func (m *ImmutablePrefixMap[V]) WithPrefix(prefix string) iter.Seq2[string, []V] {
+  return func(yield func(string, []V) bool) {
+    node := m.root
+    key := ""
+    for node != nil && len(key) < len(prefix) {
+      rest := prefix[len(key):]
+      child := node.childFor(rest[0])
+      if child == nil || !(strings.HasPrefix(rest, child.key) || strings.HasPrefix(child.key, rest)) {
+        return
+      }
+      key += child.key
+      node = child
+    }
+    if node != nil {
+      node.ascend(key, yield)
+    }
+  }
+}
+
+// set returns a map where the values of key are the ones
+// returned by fn, given the existing ones
+func (m *ImmutablePrefixMap[V]) set(key string, fn func([]V) []V) *ImmutablePrefixMap[V] {
+  root := m.root
+  if root == nil {
+    root = &immutableNode[V]{}
+  }
+
+  root, added := root.set(key, fn)
+  n := &ImmutablePrefixMap[V]{root: root, len: m.len}
+  if added {
+    n.len++
+  }
+  return n
+}
+
+// nodeForKey returns the node holding the given
+// key or nil if no such node exists
+func (m *ImmutablePrefixMap[V]) nodeForKey(key string) *immutableNode[V] {
+  node := m.root
+  for node != nil && len(key) > 0 {
+    child := node.childFor(key[0])
+    if child == nil || !strings.HasPrefix(key, child.key) {
+      return nil
+    }
+    key = key[len(child.key):]
+    node = child
+  }
+  return node
+}
+
+// childIndex returns the index of the child whose key starts with b,
+// or the index it would be inserted at and false if there's none
+func (n *immutableNode[V]) childIndex(b byte) (int, bool) {
+  i := sort.Search(len(n.children), func(i int) bool {
+    return n.children[i].key[0] >= b
+  })
+  return i, i < len(n.children) && n.children[i].key[0] == b
+}
+
+// childFor returns the child whose key
+// starts with b or nil if there's none
+func (n *immutableNode[V]) childFor(b byte) *immutableNode[V] {
+  if i, ok := n.childIndex(b); ok {
+    return n.children[i]
+  }
+  return nil
+}
+
+// withChild returns a copy of n where the child at index i is
+// replaced with child, or removed if child is nil, or where child
+// is inserted at index i if insert is true
+func (n *immutableNode[V]) withChild(i int, child *immutableNode[V], insert bool) *immutableNode[V] {
+  c := *n
+  c.children = make([]*immutableNode[V], 0, len(n.children)+1)
+  c.children = append(c.children, n.children[:i]...)
+  if child != nil {
+    c.children = append(c.children, child)
+  }
+  if !insert {
+    i++
+  }
+  c.children = append(c.children, n.children[i:]...)
+  return &c
+}
+
+// set returns a copy of n where the values of key, relative to
+// n, are the ones returned by fn given the existing ones, and
+// whether key has been added
+func (n *immutableNode[V]) set(key string, fn func([]V) []V) (*immutableNode[V], bool) {
+  if key == "" {
+    c := *n
+    c.isKey, c.data = true, fn(n.data)
+    return &c, !n.isKey
+  }
+
+  i, ok := n.childIndex(key[0])
+  if !ok {
+    child := &immutableNode[V]{key: key, isKey: true, data: fn(nil)}
+    return n.withChild(i, child, true), true
+  }
+
+  child := n.children[i]
+  lcp := 0
+  for lcp < len(key) && lcp < len(child.key) && key[lcp] == child.key[lcp] {
+    lcp++
+  }
+  if lcp < len(child.key) {
+    // splitting the child by copying it under a new node
+    rest := *child
+    rest.key = child.key[lcp:]
+    child = &immutableNode[V]{
+      key:      child.key[:lcp],
+      children: []*immutableNode[V]{&rest},
+    }
+  }
+
+  child, added := child.set(key[lcp:], fn)
+  return n.withChild(i, child, false), added
+}
+
+// delete returns a copy of n without key, relative to n, or
+// nil if the copy would hold no key, and false if no such key
+// exists. The copy is compacted: it is merged with its only
+// child if it doesn't hold a key.
+func (n *immutableNode[V]) delete(key string) (*immutableNode[V], bool) {
+  var c *immutableNode[V]
+  if key == "" {
+    if !n.isKey {
+      return n, false
+    }
+    copied := *n
+    copied.isKey, copied.data = false, nil
+    c = &copied
+  } else {
+    i, ok := n.childIndex(key[0])
+    if !ok || !strings.HasPrefix(key, n.children[i].key) {
+      return n, false
+    }
+    child, ok := n.children[i].delete(key[len(n.children[i].key):])
+    if !ok {
+      return n, false
+    }
+    c = n.withChild(i, child, false)
+  }
+
+  switch {
+  case c.isKey:
+    return c, true
+  case len(c.children) == 0:
+    return nil, true
+  case len(c.children) == 1:
+    merged := *c.children[0]
+    merged.key = c.key + merged.key
+    return &merged, true
+  }
+  return c, true
+}
+
+// ascend yields the keys in the subtree rooted at n in ascending
+// lexicographic order, key being the one of n.
+// Returns false if yield stopped the iteration.
+func (n *immutableNode[V]) ascend(key string, yield func(string, []V) bool) bool {
+  if n.isKey && !yield(key, n.data) {
+    return false
+  }
+  for _, c := range n.children {
+    if !c.ascend(key+c.key, yield) {
+      return false
+    }
+  }
+  return true
+}
+
+// PersistentPrefixMap holds the latest version of an
+// ImmutablePrefixMap, allowing lock-free reads concurrent with
+// writes. Writes are applied with a compare-and-swap, retrying
+// if another write happened in the meantime.
+// The zero value is an empty map.
+type PersistentPrefixMap[V any] struct {
+  current atomic.Pointer[ImmutablePrefixMap[V]]
+}
+
+// NewPersistent returns a new empty PersistentPrefixMap
+func NewPersistent[V any]() *PersistentPrefixMap[V] {
+  return &PersistentPrefixMap[V]{}
+}
+
+// Snapshot returns the current version of the map,
+// which later writes don't affect
+func (p *PersistentPrefixMap[V]) Snapshot() *ImmutablePrefixMap[V] {
+  if m := p.current.Load(); m != nil {
+    return m
+  }
+  return &ImmutablePrefixMap[V]{}
+}
+
+// Update replaces the current version of the map with the one
+// returned by fn given the current one. fn may be invoked several
+// times if other writes happen concurrently and must have no side
+// effects.
+func (p *PersistentPrefixMap[V]) Update(fn func(m *ImmutablePrefixMap[V]) *ImmutablePrefixMap[V]) {
+  for {
+    current := p.current.Load()
+    m := current
+    if m == nil {
+      m = &ImmutablePrefixMap[V]{}
+    }
+    if p.current.CompareAndSwap(current, fn(m)) {
+      return
+    }
+  }
+}
+
+// Insert inserts new values in the map for the specified key,
+// appending them to the existing ones if any
+func (p *PersistentPrefixMap[V]) Insert(key string, values ...V) {
+  p.Update(func(m *ImmutablePrefixMap[V]) *ImmutablePrefixMap[V] {
+    return m.Insert(key, values...)
+  })
+}
+
+// Replace replaces the value(s) for the given key in the map
+func (p *PersistentPrefixMap[V]) Replace(key string, values ...V) {
+  p.Update(func(m *ImmutablePrefixMap[V]) *ImmutablePrefixMap[V] {
+    return m.Replace(key, values...)
+  })
+}
+
+// Delete removes the given key and its values from the map.
+// Returns false if no such key is present in the map.
+func (p *PersistentPrefixMap[V]) Delete(key string) bool {
+  var deleted bool
+  p.Update(func(m *ImmutablePrefixMap[V]) *ImmutablePrefixMap[V] {
+    m, deleted = m.Delete(key)
+    return m
+  })
+  return deleted
+}
+
+// Get returns the data associated with the given key in the map
+// or nil if no such key is present in the map
+func (p *PersistentPrefixMap[V]) Get(key string) []V {
+  return p.Snapshot().Get(key)
+}
+
+// GetByPrefix returns a flattened collection of values
+// associated with the given prefix key, sorted by key
+func (p *PersistentPrefixMap[V]) GetByPrefix(prefix string) []V {
+  return p.Snapshot().GetByPrefix(prefix)
+}
+
+// Contains checks if the given key is present in the map
+func (p *PersistentPrefixMap[V]) Contains(key string) bool {
+  return p.Snapshot().Contains(key)
+}
+
+// Len returns the number of keys in the map
+func (p *PersistentPrefixMap[V]) Len() int {
+  return p.Snapshot().Len()
+}
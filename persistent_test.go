@@ -0,0 +1,170 @@
+package prefixmap
+
+import (
+  "fmt"
+  "sync"
+  "testing"
+)
+
+func testImmutableKeys(m *ImmutablePrefixMap[string]) []string {
+  keys := []string{}
+  for key, values := range m.All() {
+    if len(values) == 0 || values[0] != key {
+      return append(keys, "unexpected values for "+key)
+    }
+    keys = append(keys, key)
+  }
+  return keys
+}
+
+func TestImmutableInsert(t *testing.T) {
+  versions := []*ImmutablePrefixMap[string]{NewImmutable[string]()}
+  for _, word := range nodeTests[0].words {
+    m := versions[len(versions)-1]
+    versions = append(versions, m.Insert(word, word))
+  }
+
+  // every version only holds the keys inserted before it
+  for i, m := range versions {
+    if m.Len() != i {
+      t.Errorf("Unexpected length of version %d: got %d", i, m.Len())
+    }
+    for j, word := range nodeTests[0].words {
+      if m.Contains(word) != (j < i) {
+        t.Errorf("Unexpected presence of '%s' in version %d", word, i)
+      }
+    }
+  }
+
+  m := versions[len(versions)-1]
+  expected := []string{"A", "romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"}
+  if keys := testImmutableKeys(m); !testStringsEq(keys, expected) {
+    t.Errorf("Unexpected keys: got %v, expected %v", keys, expected)
+  }
+  if values := m.GetByPrefix("rom"); !testStringsEq(values, []string{"romane", "romanus", "romulus"}) {
+    t.Errorf("Unexpected values by prefix: got %v", values)
+  }
+  if values := m.GetByPrefix("rubi"); !testStringsEq(values, []string{"rubicon", "rubicundus"}) {
+    t.Errorf("Unexpected values by prefix: got %v", values)
+  }
+  if m.Contains("rom") || m.Get("rom") != nil {
+    t.Errorf("Unexpected key 'rom' found")
+  }
+
+  appended := m.Insert("romane", "more")
+  if !testStringsEq(appended.Get("romane"), []string{"romane", "more"}) || !testStringsEq(m.Get("romane"), []string{"romane"}) {
+    t.Errorf("Expected values to be appended to the new version only")
+  }
+  if appended.Len() != m.Len() {
+    t.Errorf("Unexpected length after appending: got %d", appended.Len())
+  }
+
+  replaced := appended.Replace("romane", "replaced")
+  if !testStringsEq(replaced.Get("romane"), []string{"replaced"}) || len(appended.Get("romane")) != 2 {
+    t.Errorf("Expected values to be replaced in the new version only")
+  }
+}
+
+func TestImmutableSharing(t *testing.T) {
+  m := NewImmutable[string]()
+  for _, word := range nodeTests[0].words {
+    m = m.Insert(word, word)
+  }
+
+  n := m.Insert("romanes", "romanes")
+  if m.root.childFor('A') != n.root.childFor('A') {
+    t.Errorf("Expected untouched branches to be shared")
+  }
+  if m.root.childFor('r') == n.root.childFor('r') {
+    t.Errorf("Expected the path to the key to be copied")
+  }
+  mRub, nRub := m.root.childFor('r').childFor('u'), n.root.childFor('r').childFor('u')
+  if mRub == nil || mRub != nRub {
+    t.Errorf("Expected untouched sub-branches to be shared")
+  }
+}
+
+func TestImmutableDelete(t *testing.T) {
+  m := NewImmutable[string]()
+  for _, word := range nodeTests[0].words {
+    m = m.Insert(word, word)
+  }
+  original := m
+
+  for i, word := range nodeTests[0].words {
+    var ok bool
+    if m, ok = m.Delete(word); !ok {
+      t.Errorf("Expected '%s' to be deleted", word)
+    }
+    if _, ok = m.Delete(word); ok {
+      t.Errorf("Expected '%s' to be deleted once", word)
+    }
+    if m.Len() != len(nodeTests[0].words)-i-1 {
+      t.Errorf("Unexpected length after deleting '%s': got %d", word, m.Len())
+    }
+    if keys := testImmutableKeys(m); len(keys) != m.Len() {
+      t.Errorf("Unexpected keys after deleting '%s': %v", word, keys)
+    }
+  }
+  if len(m.root.children) != 0 {
+    t.Errorf("Expected the map to be empty")
+  }
+  if original.Len() != len(nodeTests[0].words) || len(testImmutableKeys(original)) != original.Len() {
+    t.Errorf("Expected the original map to be unaffected")
+  }
+
+  // the nodes left behind are compacted
+  m, _ = original.Delete("romulus")
+  m, _ = m.Delete("romanus")
+  if child := m.root.childFor('r').childFor('o'); child == nil || child.key != "omane" {
+    t.Errorf("Expected the nodes to be merged: got %v", child)
+  }
+
+  m = NewImmutable[string]().Insert("", "").Insert("a", "a")
+  if m, _ = m.Delete(""); m.Contains("") || !m.Contains("a") || m.root.key != "" {
+    t.Errorf("Expected the empty key to be deleted from the root")
+  }
+}
+
+func TestPersistentConcurrentAccess(t *testing.T) {
+  p := NewPersistent[int]()
+  const writers, keys = 4, 100
+
+  var wg sync.WaitGroup
+  for w := 0; w < writers; w++ {
+    wg.Add(2)
+    go func(w int) {
+      defer wg.Done()
+      for i := 0; i < keys; i++ {
+        p.Insert(fmt.Sprintf("key/%d/%d", w, i), i)
+      }
+    }(w)
+    go func(w int) {
+      defer wg.Done()
+      for i := 0; i < keys; i++ {
+        snapshot := p.Snapshot()
+        count := 0
+        for range snapshot.All() {
+          count++
+        }
+        if count != snapshot.Len() {
+          t.Errorf("Inconsistent snapshot: %d keys, length %d", count, snapshot.Len())
+        }
+        p.GetByPrefix(fmt.Sprintf("key/%d/", w))
+      }
+    }(w)
+  }
+  wg.Wait()
+
+  if p.Len() != writers*keys {
+    t.Errorf("Unexpected length: got %d, expected %d", p.Len(), writers*keys)
+  }
+
+  snapshot := p.Snapshot()
+  if !p.Delete("key/0/0") || p.Delete("key/0/0") {
+    t.Errorf("Expected 'key/0/0' to be deleted once")
+  }
+  if !snapshot.Contains("key/0/0") || p.Contains("key/0/0") {
+    t.Errorf("Expected the snapshot to be unaffected by the deletion")
+  }
+}
@@ -1,32 +1,43 @@
 package prefixmap
 
 import (
-  "gopkg.in/alediaferia/stackgo.v1"
+  "sort"
+  "strings"
 )
 
 // Node is a single node within
 // the map
-type Node struct {
+type Node[V any] struct {
   // true if this node is a leaf node
   IsLeaf bool
 
   // the reference to the parent node
-  Parent *Node
+  Parent *Node[V]
 
-  // the children nodes
-  Children []*Node
+  // the children nodes, sorted by key
+  Children []*Node[V]
 
   // private
   key    string
   isRoot bool
-  data   []interface{}
+  isKey  bool     // true if a value was stored for this exact key
+  count  int      // number of keys within the subtree rooted here
+  opts   *options // the options the map was created with
+
+  // the key as it was inserted, if the map normalizes keys
+  spelling string
+  data   []V
+
+  weight    float64 // the weight of the key held by this node
+  maxWeight float64 // the maximum key weight within the subtree rooted here
 }
 
-// PrefixMap type
-type PrefixMap Node
+// PrefixMap type.
+// V is the type of the values held by the map.
+type PrefixMap[V any] Node[V]
 
-func newNode() (m *Node) {
-  m = new(Node)
+func newNode[V any]() (m *Node[V]) {
+  m = new(Node[V])
 
   m.IsLeaf = false
   m.Parent = nil
@@ -34,17 +45,30 @@ func newNode() (m *Node) {
   return
 }
 
-// New returns a new empty map
-func New() *PrefixMap {
-  m := newNode()
+// New returns a new empty map holding
+// values of any type, configured with the given options.
+// It is kept for compatibility with the untyped API:
+// use NewOf for a type-safe map.
+func New(opts ...Option) *PrefixMap[any] {
+  return NewOf[any](opts...)
+}
+
+// NewOf returns a new empty map holding values
+// of type V, configured with the given options
+func NewOf[V any](opts ...Option) *PrefixMap[V] {
+  m := newNode[V]()
   m.isRoot = true
+  m.opts = &options{}
+  for _, opt := range opts {
+    opt(m.opts)
+  }
 
-  return (*PrefixMap)(m)
+  return (*PrefixMap[V])(m)
 }
 
 // Depth returns the depth of the
 // current node within the map
-func (m *Node) Depth() int {
+func (m *Node[V]) Depth() int {
   depth := 0
   parent := m.Parent
   for parent != nil {
@@ -61,12 +85,17 @@ func (m *Node) Depth() int {
 // Returns an additional bool indicating if the node key is an exact match
 // for the given key parameter or false if it is the closest match found
 // Algorithm: BFS
-func (m *Node) nodeForKey(key string, createIfMissing bool) (*Node, bool) {
-  var lastNode *Node = nil
+func (m *Node[V]) nodeForKey(key string, createIfMissing bool) (*Node[V], bool) {
+  // the empty key is held by the root itself
+  if len(key) == 0 {
+    return m, true
+  }
+
+  var lastNode *Node[V] = nil
   var currentNode = m
 
   // holds the next children to explore
-  var children []*Node
+  var children []*Node[V]
 
   var lcpI int // last lcp index
 
@@ -85,7 +114,7 @@ func (m *Node) nodeForKey(key string, createIfMissing bool) (*Node, bool) {
       break
     }
 
-    lcpI = lcpIndex(key, currentNode.key)
+    lcpI = m.opts.lcpIndex(key, currentNode.key)
 
     // current node is not the one
     if lcpI == -1 {
@@ -159,10 +188,14 @@ func (m *Node) nodeForKey(key string, createIfMissing bool) (*Node, bool) {
     //        o (string) = (some values associated with 'string' key)
     //        |
     //        o (map)    = (some values associated with 'stringmap' key)
+    //
+    // When not asked to create the node there's nothing
+    // left to look for: the key diverges from this branch.
+    break
   }
 
   if createIfMissing == true {
-    newNode := newNodeWithKey(key)
+    newNode := newNodeWithKey[V](key)
     if lastNode == nil {
       return m.appendNode(newNode), true
     }
@@ -172,7 +205,7 @@ func (m *Node) nodeForKey(key string, createIfMissing bool) (*Node, bool) {
   return lastNode, false
 }
 
-func (m *Node) split(index int) {
+func (m *Node[V]) split(index int) {
   rightKey := m.key[index:]
   leftKey := m.key[:index]
   subNode := m.copyNode()
@@ -186,63 +219,297 @@ func (m *Node) split(index int) {
   }
 
   m.key = leftKey
-  m.Children = []*Node{subNode}
-  m.data = []interface{}{}
+  m.Children = []*Node[V]{subNode}
+  m.data = []V{}
+  m.isKey = false
+  m.spelling = ""
+  m.weight = 0
   m.IsLeaf = false
 }
 
-func (m *Node) copyNode() *Node {
-  n := &Node{}
+func (m *Node[V]) copyNode() *Node[V] {
+  n := &Node[V]{}
   *n = *m
   return n
 }
 
-func newNodeWithKey(key string) *Node {
-  n := newNode()
+func newNodeWithKey[V any](key string) *Node[V] {
+  n := newNode[V]()
   n.key = key
   return n
 }
 
-func (m *Node) appendNode(n *Node) *Node {
-  m.Children = append(m.Children, n)
+// appendNode adds n to the children of m
+// keeping them sorted by key
+func (m *Node[V]) appendNode(n *Node[V]) *Node[V] {
+  i := sort.Search(len(m.Children), func(i int) bool {
+    return m.Children[i].key >= n.key
+  })
+  m.Children = append(m.Children, nil)
+  copy(m.Children[i+1:], m.Children[i:])
+  m.Children[i] = n
+  m.IsLeaf = false
   n.IsLeaf = true
   n.Parent = m
+  n.opts = m.opts
   return n
 }
 
+// removeChild detaches the given child node from m
+func (m *Node[V]) removeChild(n *Node[V]) {
+  for i, c := range m.Children {
+    if c == n {
+      m.Children = append(m.Children[:i], m.Children[i+1:]...)
+      break
+    }
+  }
+  n.Parent = nil
+  if len(m.Children) == 0 && !m.isRoot {
+    m.IsLeaf = true
+  }
+}
+
+// mergeChild is the opposite of split: it absorbs the only
+// child of m, concatenating their keys.
+func (m *Node[V]) mergeChild() {
+  child := m.Children[0]
+  m.key = m.key + child.key
+  m.data = child.data
+  m.isKey = child.isKey
+  m.spelling = child.spelling
+  m.count = child.count
+  m.weight = child.weight
+  m.maxWeight = child.maxWeight
+  m.Children = child.Children
+  m.IsLeaf = child.IsLeaf
+
+  // adjusting children parent
+  for _, c := range m.Children {
+    c.Parent = m
+  }
+}
+
+// compact restores the map compactness after some values have
+// been removed from m: walking up to the root, nodes holding no value
+// are pruned if they have no children or merged with their only child.
+// Subtree weights are updated accordingly.
+func (m *Node[V]) compact() {
+  node := m
+  for !node.isRoot && !node.isKey {
+    parent := node.Parent
+    if len(node.Children) == 0 {
+      parent.removeChild(node)
+      node = parent
+      continue
+    }
+    if len(node.Children) == 1 {
+      node.mergeChild()
+    }
+    break
+  }
+  node.updateWeights()
+}
+
+// nodeForPrefix returns the topmost node whose subtree holds
+// all the keys starting with the given prefix, or nil if no
+// such key is present in the map.
+// The returned node key may be longer than the prefix.
+func (m *Node[V]) nodeForPrefix(prefix string) *Node[V] {
+  node := m
+  for len(prefix) > 0 {
+    child := node.childFor(prefix)
+    if child == nil {
+      return nil
+    }
+    lcpI := m.opts.lcpIndex(prefix, child.key)
+    if lcpI == len(prefix)-1 {
+      return child
+    }
+    if lcpI < len(child.key)-1 {
+      return nil
+    }
+    prefix = prefix[lcpI+1:]
+    node = child
+  }
+
+  return node
+}
+
+// eachKeyPrefixOf walks down the map along the given input and
+// invokes fn for every node holding a key that is a prefix of input,
+// from the shortest to the longest, along with the key length.
+// The walk stops as soon as fn returns false.
+func (m *Node[V]) eachKeyPrefixOf(input string, fn func(node *Node[V], length int) bool) {
+  node := m
+  length := 0
+  for {
+    if node.isKey && !fn(node, length) {
+      return
+    }
+    if length == len(input) {
+      return
+    }
+    child := node.childFor(input[length:])
+    if child == nil || !strings.HasPrefix(input[length:], child.key) {
+      return
+    }
+    length += len(child.key)
+    node = child
+  }
+}
+
+// childFor returns the child whose key starts like s
+// or nil if there's none.
+// Children keys start with distinct bytes, or with distinct
+// runes when the map is rune-aware.
+func (m *Node[V]) childFor(s string) *Node[V] {
+  i := sort.Search(len(m.Children), func(i int) bool {
+    return m.Children[i].key[0] >= s[0]
+  })
+  n := m.opts.firstLen(s)
+  for ; i < len(m.Children) && m.Children[i].key[0] == s[0]; i++ {
+    c := m.Children[i]
+    if m.opts.firstLen(c.key) == n && c.key[:n] == s[:n] {
+      return c
+    }
+  }
+  return nil
+}
+
+// addCount adds delta to the keys count of m
+// and of all its ancestors
+func (m *Node[V]) addCount(delta int) {
+  for node := m; node != nil; node = node.Parent {
+    node.count += delta
+  }
+}
+
+// setKey marks m as holding a key, retaining
+// the key spelling if the map normalizes keys
+func (m *Node[V]) setKey(spelling string) {
+  if !m.isKey {
+    m.isKey = true
+    if m.opts.normalizer != nil {
+      m.spelling = spelling
+    }
+    m.addCount(1)
+    m.updateWeights()
+  }
+}
+
+// keyOf returns the key to report for m given its
+// normalized key, that is its original spelling if any
+func (m *Node[V]) keyOf(key string) string {
+  if m.spelling != "" {
+    return m.spelling
+  }
+  return key
+}
+
 // Insert inserts a new value in the map for the specified key
 // If the key is already present in the map, the value is appended
 // to the values list associated with the given key
-func (m *PrefixMap) Insert(key string, values ...interface{}) {
-  mNode := (*Node)(m)
-  n, _ := mNode.nodeForKey(key, true)
+func (m *PrefixMap[V]) Insert(key string, values ...V) {
+  mNode := (*Node[V])(m)
+  n, _ := mNode.nodeForKey(m.opts.normalize(key), true)
   n.data = append(n.data, values...)
+  n.setKey(key)
 }
 
 // Replace replaces the value(s) for the given key in the map
 // with the give ones. If no such key is present, this method
 // behaves the same as Insert
-func (m *PrefixMap) Replace(key string, values ...interface{}) {
-  mNode := (*Node)(m)
-  n, _ := mNode.nodeForKey(key, true)
+func (m *PrefixMap[V]) Replace(key string, values ...V) {
+  mNode := (*Node[V])(m)
+  n, _ := mNode.nodeForKey(m.opts.normalize(key), true)
   n.data = values
+  n.setKey(key)
+}
+
+// Delete removes the given key and its values from the map.
+// Nodes left behind by the removal are compacted so that
+// the map looks as if the key had never been inserted.
+// Returns false if no such key is present in the map.
+func (m *PrefixMap[V]) Delete(key string) bool {
+  mNode := (*Node[V])(m)
+  n, exactMatch := mNode.nodeForKey(m.opts.normalize(key), false)
+  if n == nil || !exactMatch || !n.isKey {
+    return false
+  }
+
+  n.data = nil
+  n.isKey = false
+  n.spelling = ""
+  n.weight = 0
+  n.addCount(-1)
+  n.compact()
+
+  return true
+}
+
+// DeletePrefix removes all the keys starting with the given prefix
+// from the map, compacting the remaining nodes.
+// Returns the number of keys removed.
+func (m *PrefixMap[V]) DeletePrefix(prefix string) int {
+  mNode := (*Node[V])(m)
+  n := mNode.nodeForPrefix(m.opts.normalize(prefix))
+  if n == nil {
+    return 0
+  }
+
+  count := n.count
+  if n.isRoot {
+    n.Children = nil
+    n.data = nil
+    n.isKey = false
+    n.spelling = ""
+    n.count = 0
+    n.weight = 0
+    n.updateWeights()
+    return count
+  }
+
+  parent := n.Parent
+  parent.removeChild(n)
+  parent.addCount(-count)
+  parent.compact()
+
+  return count
+}
+
+// Len returns the number of keys in the map
+func (m *PrefixMap[V]) Len() int {
+  return m.count
+}
+
+// CountPrefix returns the number of keys in the
+// map starting with the given prefix.
+// complexity: O(len(prefix))
+func (m *PrefixMap[V]) CountPrefix(prefix string) int {
+  mNode := (*Node[V])(m)
+  n := mNode.nodeForPrefix(m.opts.normalize(prefix))
+  if n == nil {
+    return 0
+  }
+
+  return n.count
 }
 
 // Contains checks if the given key is present in the map
 // In this case, an exact match case is considered
 // If you're interested in prefix-based check: ContainsPrefix
-func (m *PrefixMap) Contains(key string) bool {
-  mNode := (*Node)(m)
-  retrievedNode, exactMatch := mNode.nodeForKey(key, false)
-  return retrievedNode != nil && exactMatch
+func (m *PrefixMap[V]) Contains(key string) bool {
+  mNode := (*Node[V])(m)
+  retrievedNode, exactMatch := mNode.nodeForKey(m.opts.normalize(key), false)
+  return retrievedNode != nil && exactMatch && retrievedNode.isKey
 }
 
 // Get returns the data associated with the given key in the map
 // or nil if no such key is present in the map
-func (m *PrefixMap) Get(key string) []interface{} {
-  mNode := (*Node)(m)
-  retrievedNode, exactMatch := mNode.nodeForKey(key, false)
-  if !exactMatch {
+func (m *PrefixMap[V]) Get(key string) []V {
+  mNode := (*Node[V])(m)
+  retrievedNode, exactMatch := mNode.nodeForKey(m.opts.normalize(key), false)
+  if !exactMatch || !retrievedNode.isKey {
     return nil
   }
 
@@ -250,23 +517,26 @@ func (m *PrefixMap) Get(key string) []interface{} {
 }
 
 // GetByPrefix returns a flattened collection of values
-// associated with the given prefix key
-func (m *PrefixMap) GetByPrefix(key string) []interface{} {
-  mNode := (*Node)(m)
-  retrievedNode, _ := mNode.nodeForKey(key, false)
+// associated with the given prefix key, sorted by key
+func (m *PrefixMap[V]) GetByPrefix(key string) []V {
+  mNode := (*Node[V])(m)
+  retrievedNode := mNode.nodeForPrefix(m.opts.normalize(key))
   if retrievedNode == nil {
-    return []interface{}{}
+    return []V{}
   }
 
   // now, fetching all the values (DFS)
-  stack := stackgo.NewStack()
-  values := []interface{}{}
-  stack.Push(retrievedNode)
-  for stack.Size() > 0 {
-    node := stack.Pop().(*Node)
+  stack := []*Node[V]{}
+  values := []V{}
+  stack = append(stack, retrievedNode)
+  for len(stack) > 0 {
+    node := stack[len(stack)-1]
+    stack = stack[:len(stack)-1]
     values = append(values, node.data...)
-    for _, c := range node.Children {
-      stack.Push(c)
+    // pushing children backwards so that
+    // they are popped in lexicographic order
+    for i := len(node.Children) - 1; i >= 0; i-- {
+      stack = append(stack, node.Children[i])
     }
   }
 
@@ -274,10 +544,56 @@ func (m *PrefixMap) GetByPrefix(key string) []interface{} {
 }
 
 // ContainsPrefix checks if the given prefix is present as key in the map
-func (m *PrefixMap) ContainsPrefix(key string) bool {
-  mNode := (*Node)(m)
-  retrievedNode, _ := mNode.nodeForKey(key, false)
-  return retrievedNode != nil
+func (m *PrefixMap[V]) ContainsPrefix(key string) bool {
+  mNode := (*Node[V])(m)
+  retrievedNode := mNode.nodeForPrefix(m.opts.normalize(key))
+  return retrievedNode != nil && (retrievedNode.isKey || len(retrievedNode.Children) > 0)
+}
+
+// LongestPrefix returns the longest key in the map that is
+// a prefix of the given input, along with its values.
+// ok is false if no key in the map is a prefix of input.
+func (m *PrefixMap[V]) LongestPrefix(input string) (key string, values []V, ok bool) {
+  mNode := (*Node[V])(m)
+  input = m.opts.normalize(input)
+  mNode.eachKeyPrefixOf(input, func(node *Node[V], length int) bool {
+    key, values, ok = node.keyOf(input[:length]), node.data, true
+    return true
+  })
+
+  return
+}
+
+// ShortestPrefix returns the shortest key in the map that is
+// a prefix of the given input, along with its values.
+// ok is false if no key in the map is a prefix of input.
+func (m *PrefixMap[V]) ShortestPrefix(input string) (key string, values []V, ok bool) {
+  mNode := (*Node[V])(m)
+  input = m.opts.normalize(input)
+  mNode.eachKeyPrefixOf(input, func(node *Node[V], length int) bool {
+    key, values, ok = node.keyOf(input[:length]), node.data, true
+    return false
+  })
+
+  return
+}
+
+// PrefixesOf returns all the keys in the map that are
+// a prefix of the given input, from the shortest to the longest
+func (m *PrefixMap[V]) PrefixesOf(input string) []Prefix[V] {
+  mNode := (*Node[V])(m)
+  prefixes := []Prefix[V]{}
+  input = m.opts.normalize(input)
+  mNode.eachKeyPrefixOf(input, func(node *Node[V], length int) bool {
+    prefixes = append(prefixes, Prefix[V]{
+      node:   node,
+      Key:    node.keyOf(input[:length]),
+      Values: node.data,
+    })
+    return true
+  })
+
+  return prefixes
 }
 
 // Key Retrieves current node key
@@ -285,7 +601,7 @@ func (m *PrefixMap) ContainsPrefix(key string) bool {
 // is the number of nodes in the map.
 // Number of nodes in the map cannot exceed
 // number of keys + 1.
-func (m *Node) Key() string {
+func (m *Node[V]) Key() string {
   node := m
   k := make([]byte, 0, len(m.key))
   for node != nil && node.isRoot != true {
@@ -301,43 +617,44 @@ func (m *Node) Key() string {
 // Returning skipBranch = true will make the traversal skip the current branch
 // and jump to the sibling node in the map. Returning halt = true, instead,
 // will halt the traversal altogether.
-type PrefixCallback func(prefix Prefix) (skipBranch bool, halt bool)
+type PrefixCallback[V any] func(prefix Prefix[V]) (skipBranch bool, halt bool)
 
 // Prefix holds prefix information
 // passed to the PrefixCallback instance by
 // the EachPrefifx method.
-type Prefix struct {
-  node *Node
+type Prefix[V any] struct {
+  node *Node[V]
 
   // The current prefix string
   Key string
 
   // The values associated to the current prefix
-  Values []interface{}
+  Values []V
 }
 
 // Depth returns the depth of the corresponding
 // node for this prefix in the map.
-func (p *Prefix) Depth() int {
+func (p *Prefix[V]) Depth() int {
   return p.node.Depth()
 }
 
 // EachPrefix iterates over the prefixes contained in the
-// map using a DFS algorithm. The callback can be used to skip
+// map in lexicographic order using a DFS algorithm. The callback can be used to skip
 // a prefix branch altogether or halt the iteration.
-func (m *PrefixMap) EachPrefix(callback PrefixCallback) {
-  mNode := (*Node)(m)
-  stack := stackgo.NewStack()
+func (m *PrefixMap[V]) EachPrefix(callback PrefixCallback[V]) {
+  mNode := (*Node[V])(m)
+  stack := []*Node[V]{}
   prefix := []byte{}
 
   skipsubtree := false
   halt := false
-  addedLengths := stackgo.NewStack()
+  addedLengths := []int{}
   lastDepth := mNode.Depth()
 
-  stack.Push(mNode)
-  for stack.Size() != 0 {
-    node := stack.Pop().(*Node)
+  stack = append(stack, mNode)
+  for len(stack) != 0 {
+    node := stack[len(stack)-1]
+    stack = stack[:len(stack)-1]
     if !node.isRoot {
       // if we are now going up
       // in the radix (e.g. we have
@@ -347,19 +664,20 @@ func (m *PrefixMap) EachPrefix(callback PrefixCallback) {
       if lastDepth >= node.Depth() {
         var length = 0
         for i := 0; i < (lastDepth-currentDepth)+1; i++ {
-          length += addedLengths.Pop().(int)
+          length += addedLengths[len(addedLengths)-1]
+          addedLengths = addedLengths[:len(addedLengths)-1]
         }
         prefix = prefix[:len(prefix)-length]
       }
       lastDepth = currentDepth
       prefix = append(prefix, node.key...)
-      addedLengths.Push(len(node.key))
+      addedLengths = append(addedLengths, len(node.key))
 
       // building the info
       // data to pass to the callback
-      info := Prefix{
+      info := Prefix[V]{
         node:   node,
-        Key:    string(prefix),
+        Key:    node.keyOf(string(prefix)),
         Values: node.data,
       }
 
@@ -371,8 +689,10 @@ func (m *PrefixMap) EachPrefix(callback PrefixCallback) {
         continue
       }
     }
-    for i := 0; i < len(node.Children); i++ {
-      stack.Push(node.Children[i])
+    // pushing children backwards so that
+    // they are popped in lexicographic order
+    for i := len(node.Children) - 1; i >= 0; i-- {
+      stack = append(stack, node.Children[i])
     }
   }
 }
@@ -11,7 +11,7 @@ import (
 // testing basic insert functionality
 func TestInsert(t *testing.T) {
   m := New()
-  n := (*Node)(m)
+  n := (*Node[any])(m)
   expectedValues := []interface{}{"bar", "baz", "quz"}
   m.Insert("foo", expectedValues...)
 
@@ -21,9 +21,36 @@ func TestInsert(t *testing.T) {
   }
 }
 
+func TestTypedMap(t *testing.T) {
+  m := NewOf[int]()
+  m.Insert("one", 1)
+  m.Insert("only", 2, 3)
+
+  if data := m.Get("one"); len(data) != 1 || data[0] != 1 {
+    t.Errorf("Unexpected value for key 'one': expected ([1]), got (%v)", data)
+  }
+
+  sum := 0
+  for _, v := range m.GetByPrefix("o") {
+    sum += v
+  }
+  if sum != 6 {
+    t.Errorf("Unexpected sum of values for prefix 'o': got %d, expected %d", sum, 6)
+  }
+
+  prefixes := 0
+  m.EachPrefix(func(prefix Prefix[int]) (bool, bool) {
+    prefixes++
+    return false, false
+  })
+  if prefixes != 3 {
+    t.Errorf("Unexpected number of prefixes: got %d, expected %d", prefixes, 3)
+  }
+}
+
 func TestInsertWithLengtheningKeys(t *testing.T) {
   m := New()
-  n := (*Node)(m)
+  n := (*Node[any])(m)
 
   testCases := []struct {
     key1, key2 string
@@ -53,7 +80,7 @@ for _, tc := range testCases {
 // testing if insert appends values to the node
 func TestInsertAppends(t *testing.T) {
   m := New()
-  n := (*Node)(m)
+  n := (*Node[any])(m)
   expectedValues := []interface{}{"bar", "baz", "quz"}
   m.Insert("foo", expectedValues[:2]...) // first two values
   m.Insert("foo", expectedValues[2:]...) // rest
@@ -66,7 +93,7 @@ func TestInsertAppends(t *testing.T) {
 
 func TestReplaceReplaces(t *testing.T) {
   m := New()
-  n := (*Node)(m)
+  n := (*Node[any])(m)
   expectedValues := []interface{}{"bar", "baz", "quz"}
   m.Replace("foo", expectedValues[:2]...) // first two values
   m.Replace("foo", expectedValues[2:]...) // rest
@@ -79,7 +106,7 @@ func TestReplaceReplaces(t *testing.T) {
 
 func TestInsertSubstringKeys(t *testing.T) {
   m := New()
-  n := (*Node)(m)
+  n := (*Node[any])(m)
   expectedValues := []interface{}{"Diaferia", "alediaferia", "adiaferia"}
   m.Insert("stringmap", expectedValues[:2]...) // first two values
   m.Insert("string", expectedValues[2:]...)    // rest
@@ -132,7 +159,7 @@ func TestPrefixAsKey(t *testing.T) {
 
 for _, testCase := range testCases {
   m := New()
-  n := (*Node)(m)
+  n := (*Node[any])(m)
   m.Insert(testCase.insertKey, testCase.values...)
   if node, _ := n.nodeForKey(testCase.getKey, false); testEq(node.data, testCase.values) != true {
     t.Errorf("Unexpected value for node '%s': expected (%v), got (%v)", testCase.getKey, testCase.values, node.data)
@@ -149,7 +176,7 @@ func TestGetByPrefix(t *testing.T) {
     {
     keys:           []interface{}{"prefix1", "prefix2", "prefix3"},
     prefix:         "prefix",
-    expectedValues: []interface{}{"prefix3", "prefix2", "prefix1"},
+    expectedValues: []interface{}{"prefix1", "prefix2", "prefix3"},
   },
   {
   keys:           []interface{}{"a", "b", "c"},
@@ -207,7 +234,7 @@ for _, testCase := range lcpTestCases {
 
 func TestSplit(t *testing.T) {
   m := New()
-  n := (*Node)(m)
+  n := (*Node[any])(m)
   m.Insert("stringmap", "a", "b", "c")
 
   node, _ := n.nodeForKey("stringmap", false)
@@ -253,7 +280,7 @@ var nodeTests = []nodeTest{
 func TestNodeCount(t *testing.T) {
   for _, v := range nodeTests {
     m := New()
-    n := (*Node)(m)
+    n := (*Node[any])(m)
     // appending nodes
     for _, w := range v.words {
       m.Insert(w, w)
@@ -283,7 +310,7 @@ func TestPrefixIteration(t *testing.T) {
     }{
     {
     keys:             []string{"benchmark", "bench", "bob", "blueray", "bluetooth"},
-    expectedPrefixes: []interface{}{"b", "blue", "bluetooth", "blueray", "bob", "bench", "benchmark"},
+    expectedPrefixes: []interface{}{"b", "bench", "benchmark", "blue", "blueray", "bluetooth", "bob"},
   },
 }
 
@@ -294,7 +321,7 @@ for _, tc := range testCases {
   }
 
   foundPrefixes := []interface{}{}
-  m.EachPrefix(func(prefix Prefix) (bool, bool) {
+  m.EachPrefix(func(prefix Prefix[any]) (bool, bool) {
     foundPrefixes = append(foundPrefixes, prefix.Key)
     return false, false
   })
@@ -324,6 +351,17 @@ func TestContains(t *testing.T) {
   },
 },
 },
+{
+keys: []string{"romane", "romanus"},
+expectedResults: []expectedResult{
+  {
+  "roman", false,
+},
+{
+"romanus", true,
+},
+},
+},
 }
 
 for _, tc := range testCases {
@@ -363,6 +401,9 @@ func TestContainsPrefix(t *testing.T) {
 {
 "lang", false,
 },
+{
+"foobarbaz", false,
+},
 },
 },
 }
@@ -375,10 +416,281 @@ for _, tc := range testCases {
   for _, er := range tc.expectedResults {
     if got := m.ContainsPrefix(er.key); got != er.result {
       t.Errorf("Unexpected result for key %s: got %v, expected %v", er.key, got, er.result)
-      (*Node)(m).print(-1)
+      (*Node[any])(m).print(-1)
+    }
+  }
+}
+}
+
+func TestDelete(t *testing.T) {
+  testCases := []struct {
+    keys          []string
+    deleteKey     string
+    deleted       bool
+    expectedNodes int
+  }{
+    {
+      keys:          []string{"string", "stringmap"},
+      deleteKey:     "string",
+      deleted:       true,
+      expectedNodes: 2,
+    },
+    {
+      keys:          []string{"romane", "romanus", "romulus"},
+      deleteKey:     "romanus",
+      deleted:       true,
+      expectedNodes: 4,
+    },
+    {
+      keys:          []string{"romane", "romanus"},
+      deleteKey:     "roman",
+      deleted:       false,
+      expectedNodes: 4,
+    },
+    {
+      keys:          []string{"a", "b"},
+      deleteKey:     "c",
+      deleted:       false,
+      expectedNodes: 3,
+    },
+  }
+
+  for _, tc := range testCases {
+    m := New()
+    n := (*Node[any])(m)
+    for _, key := range tc.keys {
+      m.Insert(key, key)
+    }
+
+    if deleted := m.Delete(tc.deleteKey); deleted != tc.deleted {
+      t.Errorf("Unexpected result deleting '%s': got %v, expected %v", tc.deleteKey, deleted, tc.deleted)
+    }
+    if m.Contains(tc.deleteKey) {
+      t.Errorf("Key '%s' is not expected to be in the map", tc.deleteKey)
+    }
+    for _, key := range tc.keys {
+      if key == tc.deleteKey {
+        continue
+      }
+      if data := m.Get(key); testEq(data, []interface{}{key}) != true {
+        t.Errorf("Unexpected value for key '%s': expected (%v), got (%v)", key, []interface{}{key}, data)
+      }
+    }
+    if count := n.countNodes(); count != tc.expectedNodes {
+      n.print(-1)
+      t.Errorf("Unexpected node count: got %d, expected %d", count, tc.expectedNodes)
+    }
+  }
+}
+
+func TestDeleteCompacts(t *testing.T) {
+  for _, v := range nodeTests {
+    m := New()
+    n := (*Node[any])(m)
+    for _, w := range v.words {
+      m.Insert(w, w)
+    }
+    m.Insert("extra", "extra")
+    m.Insert("rubiconus", "rubiconus")
+    m.Delete("extra")
+    m.Delete("rubiconus")
+
+    if count := n.countNodes(); count != v.nodes {
+      n.print(-1)
+      t.Errorf("Unexpected node count after deletion: got %d, expected %d", count, v.nodes)
+    }
+  }
+}
+
+func TestDeletePrefix(t *testing.T) {
+  testCases := []struct {
+    keys          []string
+    prefix        string
+    deleted       int
+    remaining     []string
+    expectedNodes int
+  }{
+    {
+      keys:          []string{"romane", "romanus", "romulus", "rubens"},
+      prefix:        "rom",
+      deleted:       3,
+      remaining:     []string{"rubens"},
+      expectedNodes: 2,
+    },
+    {
+      keys:          []string{"romane", "romanus", "romulus"},
+      prefix:        "roma",
+      deleted:       2,
+      remaining:     []string{"romulus"},
+      expectedNodes: 2,
+    },
+    {
+      keys:          []string{"foo", "bar"},
+      prefix:        "baz",
+      deleted:       0,
+      remaining:     []string{"foo", "bar"},
+      expectedNodes: 3,
+    },
+    {
+      keys:          []string{"foo", "bar"},
+      prefix:        "",
+      deleted:       2,
+      remaining:     []string{},
+      expectedNodes: 1,
+    },
+  }
+
+  for _, tc := range testCases {
+    m := New()
+    n := (*Node[any])(m)
+    for _, key := range tc.keys {
+      m.Insert(key, key)
+    }
+
+    if deleted := m.DeletePrefix(tc.prefix); deleted != tc.deleted {
+      t.Errorf("Unexpected number of keys deleted for prefix '%s': got %d, expected %d", tc.prefix, deleted, tc.deleted)
+    }
+    for _, key := range tc.remaining {
+      if !m.Contains(key) {
+        t.Errorf("Key '%s' is expected to be in the map", key)
+      }
+    }
+    if count := n.countNodes(); count != tc.expectedNodes {
+      n.print(-1)
+      t.Errorf("Unexpected node count: got %d, expected %d", count, tc.expectedNodes)
     }
   }
 }
+
+func TestLongestPrefix(t *testing.T) {
+  testCases := []struct {
+    keys             []string
+    input            string
+    longest          string
+    shortest         string
+    ok               bool
+    expectedPrefixes []string
+  }{
+    {
+      keys:             []string{"/", "/api", "/api/v1", "/apis"},
+      input:            "/api/v1/users",
+      longest:          "/api/v1",
+      shortest:         "/",
+      ok:               true,
+      expectedPrefixes: []string{"/", "/api", "/api/v1"},
+    },
+    {
+      keys:             []string{"romane", "romanus", "roman"},
+      input:            "romanes",
+      longest:          "romane",
+      shortest:         "roman",
+      ok:               true,
+      expectedPrefixes: []string{"roman", "romane"},
+    },
+    {
+      keys:             []string{"romane", "romanus"},
+      input:            "roman",
+      ok:               false,
+      expectedPrefixes: []string{},
+    },
+    {
+      keys:             []string{"foo", "bar"},
+      input:            "baz",
+      ok:               false,
+      expectedPrefixes: []string{},
+    },
+  }
+
+  for _, tc := range testCases {
+    m := New()
+    for _, key := range tc.keys {
+      m.Insert(key, key)
+    }
+
+    key, values, ok := m.LongestPrefix(tc.input)
+    if ok != tc.ok || key != tc.longest {
+      t.Errorf("Unexpected longest prefix of '%s': got ('%s', %v), expected ('%s', %v)", tc.input, key, ok, tc.longest, tc.ok)
+    }
+    if ok && testEq(values, []interface{}{tc.longest}) != true {
+      t.Errorf("Unexpected values for longest prefix '%s': got %v", key, values)
+    }
+
+    key, _, ok = m.ShortestPrefix(tc.input)
+    if ok != tc.ok || key != tc.shortest {
+      t.Errorf("Unexpected shortest prefix of '%s': got ('%s', %v), expected ('%s', %v)", tc.input, key, ok, tc.shortest, tc.ok)
+    }
+
+    prefixes := m.PrefixesOf(tc.input)
+    if len(prefixes) != len(tc.expectedPrefixes) {
+      t.Errorf("Unexpected prefixes of '%s': got %v, expected %v", tc.input, prefixes, tc.expectedPrefixes)
+      continue
+    }
+    for i, p := range prefixes {
+      if p.Key != tc.expectedPrefixes[i] {
+        t.Errorf("Unexpected prefix at %d: got '%s', expected '%s'", i, p.Key, tc.expectedPrefixes[i])
+      }
+    }
+  }
+}
+
+func TestLen(t *testing.T) {
+  m := New()
+  if l := m.Len(); l != 0 {
+    t.Errorf("Unexpected length for an empty map: got %d, expected %d", l, 0)
+  }
+
+  for _, v := range nodeTests {
+    m := New()
+    for _, w := range v.words {
+      m.Insert(w, w)
+    }
+    // existing keys are not counted twice
+    m.Insert(v.words[0], v.words[0])
+    m.Replace(v.words[1], v.words[1])
+
+    if l := m.Len(); l != len(v.words) {
+      t.Errorf("Unexpected length: got %d, expected %d", l, len(v.words))
+    }
+
+    m.Delete(v.words[0])
+    m.Delete("missing")
+    if l := m.Len(); l != len(v.words)-1 {
+      t.Errorf("Unexpected length after deletion: got %d, expected %d", l, len(v.words)-1)
+    }
+  }
+}
+
+func TestCountPrefix(t *testing.T) {
+  testCases := []struct {
+    prefix string
+    count  int
+  }{
+    {"", 8},
+    {"r", 7},
+    {"rom", 3},
+    {"roman", 2},
+    {"romanus", 1},
+    {"rub", 4},
+    {"rubic", 2},
+    {"x", 0},
+    {"romanusx", 0},
+  }
+
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  for _, tc := range testCases {
+    if count := m.CountPrefix(tc.prefix); count != tc.count {
+      t.Errorf("Unexpected count for prefix '%s': got %d, expected %d", tc.prefix, count, tc.count)
+    }
+  }
+
+  m.DeletePrefix("rub")
+  if count := m.CountPrefix("r"); count != 3 {
+    t.Errorf("Unexpected count for prefix 'r' after deletion: got %d, expected %d", count, 3)
+  }
 }
 
 func BenchmarkInsertAllocations(b *testing.B) {
@@ -492,7 +804,7 @@ func BenchmarkInsertNativeMapAllocations(b *testing.B) {
       found = m.Contains(word)
       if found != true {
         b.Errorf("Unexpected: couldn't find word '%s'", word)
-        n := (*Node)(m)
+        n := (*Node[any])(m)
         if node, _ := n.nodeForKey(word, false); node != nil {
           k := n.Key()
           b.Logf("Node has unexpected key: %s (%v)", k, []byte(k))
@@ -572,8 +884,8 @@ func BenchmarkInsertNativeMapAllocations(b *testing.B) {
       return true
     }
 
-    func (m *Node) countNodes() int {
-      queue := newQueue()
+    func (m *Node[V]) countNodes() int {
+      queue := newQueue[V]()
 
       queue.enqueue(m)
       count := 0
@@ -589,8 +901,8 @@ func BenchmarkInsertNativeMapAllocations(b *testing.B) {
       return count
     }
 
-    func (m *Node) print(maxDepth int) {
-      q := newQueue()
+    func (m *Node[V]) print(maxDepth int) {
+      q := newQueue[V]()
       last_depth := m.Depth()
 
       fmt.Print("Map: \n")
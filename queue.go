@@ -4,13 +4,13 @@ package prefixmap
 
 const q_PAGE_SIZE = 4096 // common page size
 
-type queue struct {
-    q                []*Node
-    pages            [][]*Node
+type queue[V any] struct {
+    q                []*Node[V]
+    pages            [][]*Node[V]
     h, t, page_index int
 }
 
-func (q *queue) enqueue(node *Node) {
+func (q *queue[V]) enqueue(node *Node[V]) {
     if q.t == cap(q.q) {
         // moving to the next page
         q.page_index += 1
@@ -18,7 +18,7 @@ func (q *queue) enqueue(node *Node) {
         // incrementing pages slice
         // if no empty pages are available
         if q.page_index == len(q.pages) {
-            page := make([]*Node, q_PAGE_SIZE)
+            page := make([]*Node[V], q_PAGE_SIZE)
             q.pages = append(q.pages, page)
         }
         q.q = q.pages[q.page_index]
@@ -31,11 +31,11 @@ func (q *queue) enqueue(node *Node) {
     q.t += 1
 }
 
-func (q *queue) isEmpty() bool {
+func (q *queue[V]) isEmpty() bool {
     return q.h == q.t
 }
 
-func (q *queue) dequeue() (node *Node) {
+func (q *queue[V]) dequeue() (node *Node[V]) {
     if q.h == q.t {
         if q.page_index > 0 {
             q.page_index -= 1
@@ -53,15 +53,15 @@ func (q *queue) dequeue() (node *Node) {
     return
 }
 
-func (q *queue) clear() {
+func (q *queue[V]) clear() {
     q.h = 0
     q.t = 0
 }
 
-func newQueue() *queue {
-    q := new(queue)
-    q.q = make([]*Node, q_PAGE_SIZE)
-    q.pages = [][]*Node{q.q}
+func newQueue[V any]() *queue[V] {
+    q := new(queue[V])
+    q.q = make([]*Node[V], q_PAGE_SIZE)
+    q.pages = [][]*Node[V]{q.q}
     q.h = 0
     q.t = 0
     q.page_index = 0
@@ -16,10 +16,10 @@ var queue_tests = []queue_test{
 
 func Test_queue(t *testing.T) {
     for _, v := range queue_tests {
-        q := newQueue()
+        q := newQueue[any]()
         word := make([]byte, 0, len(v.w))
         for i := 0; i < len(v.w); i++ {
-            t_ := newNode()
+            t_ := newNode[any]()
             t_.key = string(append([]byte(t_.key), v.w[i]))
             q.enqueue(t_)
         }
@@ -37,8 +37,8 @@ func Test_queue(t *testing.T) {
 
 func Benchmark_queue_enqueue(b *testing.B) {
     b.ReportAllocs()
-    q := newQueue()
-    t := newNode()
+    q := newQueue[any]()
+    t := newNode[any]()
     b.StopTimer()
     b.ResetTimer()
     b.StartTimer()
@@ -49,8 +49,8 @@ func Benchmark_queue_enqueue(b *testing.B) {
 
 func Benchmark_queue_dequeue(b *testing.B) {
     b.ReportAllocs()
-    q := newQueue()
-    t := newNode()
+    q := newQueue[any]()
+    t := newNode[any]()
     b.StopTimer()
     for i := 0; i < b.N; i++ {
         q.enqueue(t)
@@ -0,0 +1,151 @@
+package prefixmap
+
+import (
+  "strings"
+)
+
+// Range iterates in ascending order over the keys
+// within the half-open interval [from, to)
+func (m *PrefixMap[V]) Range(from, to string, callback KeyCallback[V]) {
+  mNode := (*Node[V])(m)
+  from, to = m.opts.normalize(from), m.opts.normalize(to)
+  mNode.ascendFrom(nil, from, true, func(node *Node[V], key string) bool {
+    return key < to && callback(node.keyOf(key), node.data)
+  })
+}
+
+// Seek iterates in ascending order over the keys
+// greater than or equal to the given one
+func (m *PrefixMap[V]) Seek(key string, callback KeyCallback[V]) {
+  mNode := (*Node[V])(m)
+  mNode.ascendFrom(nil, m.opts.normalize(key), true, func(node *Node[V], key string) bool {
+    return callback(node.keyOf(key), node.data)
+  })
+}
+
+// Floor returns the greatest key in the map less than
+// or equal to the given one, along with its values.
+// ok is false if there's no such key.
+func (m *PrefixMap[V]) Floor(key string) (floor string, values []V, ok bool) {
+  mNode := (*Node[V])(m)
+  mNode.descendTo(nil, m.opts.normalize(key), true, func(node *Node[V], k string) bool {
+    floor, values, ok = node.keyOf(k), node.data, true
+    return false
+  })
+
+  return
+}
+
+// Ceiling returns the least key in the map greater than
+// or equal to the given one, along with its values.
+// ok is false if there's no such key.
+func (m *PrefixMap[V]) Ceiling(key string) (ceiling string, values []V, ok bool) {
+  mNode := (*Node[V])(m)
+  mNode.ascendFrom(nil, m.opts.normalize(key), true, func(node *Node[V], k string) bool {
+    ceiling, values, ok = node.keyOf(k), node.data, true
+    return false
+  })
+
+  return
+}
+
+// Predecessor returns the greatest key in the map strictly
+// less than the given one, along with its values.
+// ok is false if there's no such key.
+func (m *PrefixMap[V]) Predecessor(key string) (predecessor string, values []V, ok bool) {
+  mNode := (*Node[V])(m)
+  mNode.descendTo(nil, m.opts.normalize(key), false, func(node *Node[V], k string) bool {
+    predecessor, values, ok = node.keyOf(k), node.data, true
+    return false
+  })
+
+  return
+}
+
+// Successor returns the least key in the map strictly
+// greater than the given one, along with its values.
+// ok is false if there's no such key.
+func (m *PrefixMap[V]) Successor(key string) (successor string, values []V, ok bool) {
+  mNode := (*Node[V])(m)
+  mNode.ascendFrom(nil, m.opts.normalize(key), false, func(node *Node[V], k string) bool {
+    successor, values, ok = node.keyOf(k), node.data, true
+    return false
+  })
+
+  return
+}
+
+// Min returns the least key in the map along with its values.
+// ok is false if the map is empty.
+func (m *PrefixMap[V]) Min() (key string, values []V, ok bool) {
+  m.Ascend(func(k string, v []V) bool {
+    key, values, ok = k, v, true
+    return false
+  })
+
+  return
+}
+
+// Max returns the greatest key in the map along with its values.
+// ok is false if the map is empty.
+func (m *PrefixMap[V]) Max() (key string, values []V, ok bool) {
+  m.Descend(func(k string, v []V) bool {
+    key, values, ok = k, v, true
+    return false
+  })
+
+  return
+}
+
+// ascendFrom is like ascend but skips the keys less than from,
+// or equal to it unless inclusive is true.
+// Subtrees entirely preceding from are not visited at all.
+func (m *Node[V]) ascendFrom(key []byte, from string, inclusive bool, callback nodeCallback[V]) bool {
+  parentKey := key
+  key = append(key, m.key...)
+  switch k := string(key); {
+  case k > from:
+    // every key in this subtree follows from
+    return m.ascendNodes(parentKey, callback)
+  case k == from:
+    if m.isKey && inclusive && !callback(m, k) {
+      return false
+    }
+  case !strings.HasPrefix(from, k):
+    // every key in this subtree precedes from
+    return true
+  }
+
+  for _, c := range m.Children {
+    if !c.ascendFrom(key, from, inclusive, callback) {
+      return false
+    }
+  }
+
+  return true
+}
+
+// descendTo is like descend but skips the keys greater than to,
+// or equal to it unless inclusive is true.
+// Subtrees entirely following to are not visited at all.
+func (m *Node[V]) descendTo(key []byte, to string, inclusive bool, callback nodeCallback[V]) bool {
+  parentKey := key
+  key = append(key, m.key...)
+  k := string(key)
+  if k > to {
+    // every key in this subtree follows to
+    return true
+  }
+  if !strings.HasPrefix(to, k) {
+    // every key in this subtree precedes to
+    return m.descendNodes(parentKey, callback)
+  }
+
+  for i := len(m.Children) - 1; i >= 0; i-- {
+    if !m.Children[i].descendTo(key, to, inclusive, callback) {
+      return false
+    }
+  }
+
+  return !m.isKey || (k == to && !inclusive) || callback(m, k)
+}
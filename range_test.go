@@ -0,0 +1,138 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestRange(t *testing.T) {
+  testCases := []struct {
+    from, to string
+    expected []string
+  }{
+    {"romane", "rubens", []string{"romane", "romanus", "romulus"}},
+    {"roman", "rubicon", []string{"romane", "romanus", "romulus", "rubens", "ruber"}},
+    {"ruber", "ruber", []string{}},
+    {"rubicundus", "z", []string{"rubicundus"}},
+    {"", "B", []string{"A"}},
+    {"s", "z", []string{}},
+  }
+
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  for _, tc := range testCases {
+    keys := []string{}
+    m.Range(tc.from, tc.to, func(key string, values []interface{}) bool {
+      keys = append(keys, key)
+      return true
+    })
+    if !testStringsEq(keys, tc.expected) {
+      t.Errorf("Unexpected keys in range ['%s', '%s'): got %v, expected %v", tc.from, tc.to, keys, tc.expected)
+    }
+  }
+}
+
+func TestSeek(t *testing.T) {
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  keys := []string{}
+  m.Seek("rubi", func(key string, values []interface{}) bool {
+    keys = append(keys, key)
+    return true
+  })
+  if expected := []string{"rubicon", "rubicundus"}; !testStringsEq(keys, expected) {
+    t.Errorf("Unexpected keys seeking 'rubi': got %v, expected %v", keys, expected)
+  }
+}
+
+func TestFloorCeiling(t *testing.T) {
+  type result struct {
+    key string
+    ok  bool
+  }
+  testCases := []struct {
+    key                             string
+    floor, ceiling, pred, successor result
+  }{
+    {
+      key:       "ruber",
+      floor:     result{"ruber", true},
+      ceiling:   result{"ruber", true},
+      pred:      result{"rubens", true},
+      successor: result{"rubicon", true},
+    },
+    {
+      key:       "rub",
+      floor:     result{"romulus", true},
+      ceiling:   result{"rubens", true},
+      pred:      result{"romulus", true},
+      successor: result{"rubens", true},
+    },
+    {
+      key:       "romanusx",
+      floor:     result{"romanus", true},
+      ceiling:   result{"romulus", true},
+      pred:      result{"romanus", true},
+      successor: result{"romulus", true},
+    },
+    {
+      key:       "A",
+      floor:     result{"A", true},
+      ceiling:   result{"A", true},
+      pred:      result{"", false},
+      successor: result{"romane", true},
+    },
+    {
+      key:       "z",
+      floor:     result{"rubicundus", true},
+      ceiling:   result{"", false},
+      pred:      result{"rubicundus", true},
+      successor: result{"", false},
+    },
+  }
+
+  m := New()
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+
+  for _, tc := range testCases {
+    if key, _, ok := m.Floor(tc.key); key != tc.floor.key || ok != tc.floor.ok {
+      t.Errorf("Unexpected floor for '%s': got ('%s', %v), expected %v", tc.key, key, ok, tc.floor)
+    }
+    if key, _, ok := m.Ceiling(tc.key); key != tc.ceiling.key || ok != tc.ceiling.ok {
+      t.Errorf("Unexpected ceiling for '%s': got ('%s', %v), expected %v", tc.key, key, ok, tc.ceiling)
+    }
+    if key, _, ok := m.Predecessor(tc.key); key != tc.pred.key || ok != tc.pred.ok {
+      t.Errorf("Unexpected predecessor for '%s': got ('%s', %v), expected %v", tc.key, key, ok, tc.pred)
+    }
+    if key, _, ok := m.Successor(tc.key); key != tc.successor.key || ok != tc.successor.ok {
+      t.Errorf("Unexpected successor for '%s': got ('%s', %v), expected %v", tc.key, key, ok, tc.successor)
+    }
+  }
+}
+
+func TestMinMax(t *testing.T) {
+  m := New()
+  if _, _, ok := m.Min(); ok {
+    t.Errorf("Min is not expected to be found in an empty map")
+  }
+  if _, _, ok := m.Max(); ok {
+    t.Errorf("Max is not expected to be found in an empty map")
+  }
+
+  for _, w := range nodeTests[0].words {
+    m.Insert(w, w)
+  }
+  if key, values, ok := m.Min(); !ok || key != "A" || testEq(values, []interface{}{"A"}) != true {
+    t.Errorf("Unexpected min: got ('%s', %v, %v)", key, values, ok)
+  }
+  if key, values, ok := m.Max(); !ok || key != "rubicundus" || testEq(values, []interface{}{"rubicundus"}) != true {
+    t.Errorf("Unexpected max: got ('%s', %v, %v)", key, values, ok)
+  }
+}
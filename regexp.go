@@ -0,0 +1,201 @@
+package prefixmap
+
+import (
+  "regexp/syntax"
+  "unicode/utf8"
+)
+
+// MatchRegexp returns the keys in the map entirely matching the
+// given regular expression, in lexicographic order.
+// The expression is implicitly anchored at both ends: use
+// a leading or trailing ".*" to match a part of the keys.
+// The expression is matched against normalized keys, if the map
+// normalizes them.
+//
+// The regular expression automaton is run along the map so that
+// the branches whose prefix can no longer match are never visited.
+func (m *PrefixMap[V]) MatchRegexp(re *syntax.Regexp) ([]Prefix[V], error) {
+  prog, err := syntax.Compile(re.Simplify())
+  if err != nil {
+    return nil, err
+  }
+
+  r := &regexpMatch[V]{
+    prog:    prog,
+    visited: make([]uint32, len(prog.Inst)),
+    states: []regexpState{{
+      pcs:  []uint32{uint32(prog.Start)},
+      prev: -1,
+    }},
+    matches: []Prefix[V]{},
+  }
+
+  mNode := (*Node[V])(m)
+  r.walk(mNode)
+
+  return r.matches, nil
+}
+
+// regexpState is the state of the automaton after
+// having consumed a key prefix
+type regexpState struct {
+  // the instructions reached by consuming
+  // the last rune, to be followed yet
+  pcs []uint32
+
+  prev  rune // the last rune consumed, -1 if none
+  start int  // the key offset of the incomplete rune, if any
+}
+
+// regexpMatch holds the state of a regular expression search.
+// The compiled program is run as a Pike VM, without captures,
+// feeding it the runes of the keys walked so far.
+type regexpMatch[V any] struct {
+  prog *syntax.Prog
+
+  // visited marks the instructions
+  // followed at the current generation
+  visited    []uint32
+  generation uint32
+
+  states  []regexpState // states[i] is the state for key[:i]
+  key     []byte
+  matches []Prefix[V]
+}
+
+// walk visits the subtree rooted at node, skipping
+// the branches the expression cannot match anymore
+func (r *regexpMatch[V]) walk(node *Node[V]) {
+  depth := len(r.key)
+  defer func() {
+    r.key = r.key[:depth]
+  }()
+
+  for i := 0; i < len(node.key); i++ {
+    r.key = append(r.key, node.key[i])
+    if !r.step(len(r.key)) {
+      return
+    }
+  }
+
+  if node.isKey && r.accepts(r.states[len(r.key)]) {
+    r.match(node, string(r.key))
+  }
+
+  for _, c := range node.Children {
+    r.walk(c)
+  }
+}
+
+// step computes the state for key[:depth]
+// from the previous one.
+// Returns false if no key continuing key[:depth] can match.
+func (r *regexpMatch[V]) step(depth int) bool {
+  if depth == len(r.states) {
+    r.states = append(r.states, regexpState{})
+  }
+  prev, next := &r.states[depth-1], &r.states[depth]
+
+  next.pcs = next.pcs[:0]
+  if !utf8.FullRune(r.key[prev.start:depth]) {
+    // waiting for the rest of the rune
+    next.pcs = append(next.pcs, prev.pcs...)
+    next.prev, next.start = prev.prev, prev.start
+    return true
+  }
+
+  rn, _ := utf8.DecodeRune(r.key[prev.start:depth])
+  next.pcs = r.consume(next.pcs, prev.pcs, prev.prev, rn)
+  next.prev, next.start = rn, depth
+
+  return len(next.pcs) > 0
+}
+
+// consume feeds rn to the given instructions and appends to
+// next the instructions reached. prev is the rune preceding rn.
+func (r *regexpMatch[V]) consume(next, pcs []uint32, prev, rn rune) []uint32 {
+  threads := r.follow(pcs, syntax.EmptyOpContext(prev, rn))
+  for _, pc := range threads {
+    inst := &r.prog.Inst[pc]
+    matches := false
+    switch inst.Op {
+    case syntax.InstRune1:
+      matches = inst.Rune[0] == rn
+    case syntax.InstRune:
+      matches = inst.MatchRune(rn)
+    case syntax.InstRuneAny:
+      matches = true
+    case syntax.InstRuneAnyNotNL:
+      matches = rn != '\n'
+    }
+    if matches {
+      next = append(next, inst.Out)
+    }
+  }
+
+  return next
+}
+
+// accepts tells if the key the state refers to matches
+func (r *regexpMatch[V]) accepts(state regexpState) bool {
+  pcs, prev := state.pcs, state.prev
+
+  // the key ends with an incomplete rune: its
+  // bytes are consumed as invalid runes
+  for i := state.start; i < len(r.key) && len(pcs) > 0; i++ {
+    pcs = r.consume(nil, pcs, prev, utf8.RuneError)
+    prev = utf8.RuneError
+  }
+
+  for _, pc := range r.follow(pcs, syntax.EmptyOpContext(prev, -1)) {
+    if r.prog.Inst[pc].Op == syntax.InstMatch {
+      return true
+    }
+  }
+
+  return false
+}
+
+// follow returns the instructions consuming a rune or matching
+// reachable from the given ones, through the empty-width
+// instructions satisfied by the given context
+func (r *regexpMatch[V]) follow(pcs []uint32, context syntax.EmptyOp) []uint32 {
+  r.generation++
+  threads := []uint32{}
+  var add func(pc uint32)
+  add = func(pc uint32) {
+    if r.visited[pc] == r.generation {
+      return
+    }
+    r.visited[pc] = r.generation
+
+    inst := &r.prog.Inst[pc]
+    switch inst.Op {
+    case syntax.InstAlt, syntax.InstAltMatch:
+      add(inst.Out)
+      add(inst.Arg)
+    case syntax.InstCapture, syntax.InstNop:
+      add(inst.Out)
+    case syntax.InstEmptyWidth:
+      if syntax.EmptyOp(inst.Arg)&^context == 0 {
+        add(inst.Out)
+      }
+    case syntax.InstFail:
+    default:
+      threads = append(threads, pc)
+    }
+  }
+  for _, pc := range pcs {
+    add(pc)
+  }
+
+  return threads
+}
+
+func (r *regexpMatch[V]) match(node *Node[V], key string) {
+  r.matches = append(r.matches, Prefix[V]{
+    node:   node,
+    Key:    node.keyOf(key),
+    Values: node.data,
+  })
+}
@@ -0,0 +1,79 @@
+package prefixmap
+
+import (
+  "regexp"
+  "regexp/syntax"
+  "testing"
+)
+
+func TestMatchRegexp(t *testing.T) {
+  keys := []string{
+    "GET /api/users",
+    "GET /api/users/42",
+    "POST /api/users",
+    "DELETE /api/users/7",
+    "GET /health",
+    "café",
+    "cafè",
+    "caffè",
+  }
+
+  testCases := []struct {
+    expr     string
+    expected []string
+  }{
+    {`GET /api/users/\d+`, []string{"GET /api/users/42"}},
+    {`(GET|POST) /api/users`, []string{"GET /api/users", "POST /api/users"}},
+    {`[A-Z]+ /api/users/[0-9]+`, []string{"DELETE /api/users/7", "GET /api/users/42"}},
+    {`.*health`, []string{"GET /health"}},
+    {`GET`, []string{}},
+    {`^GET /health$`, []string{"GET /health"}},
+    {`.*\busers\b.*`, []string{"DELETE /api/users/7", "GET /api/users", "GET /api/users/42", "POST /api/users"}},
+    {`caf.`, []string{"cafè", "café"}},
+    {`caf+\x{e8}`, []string{"caffè", "cafè"}},
+    {`(?i)CAF[É]`, []string{"café"}},
+  }
+
+  m := New()
+  for _, key := range keys {
+    m.Insert(key, key)
+  }
+
+  for _, tc := range testCases {
+    re, err := syntax.Parse(tc.expr, syntax.Perl)
+    if err != nil {
+      t.Fatalf("Cannot parse expression '%s': %v", tc.expr, err)
+    }
+    matches, err := m.MatchRegexp(re)
+    if err != nil {
+      t.Errorf("Unexpected error for expression '%s': %v", tc.expr, err)
+      continue
+    }
+
+    found := []string{}
+    for _, p := range matches {
+      found = append(found, p.Key)
+    }
+    if !testStringsEq(found, tc.expected) {
+      t.Errorf("Unexpected matches for expression '%s': got %v, expected %v", tc.expr, found, tc.expected)
+    }
+
+    // cross-checking with the regexp package
+    std := regexp.MustCompile(`^(?:` + tc.expr + `)$`)
+    for _, key := range keys {
+      if testContains(found, key) != std.MatchString(key) {
+        t.Errorf("Unexpected result for key '%s' and expression '%s'", key, tc.expr)
+      }
+    }
+  }
+}
+
+/* utils */
+func testContains(keys []string, key string) bool {
+  for _, k := range keys {
+    if k == key {
+      return true
+    }
+  }
+  return false
+}
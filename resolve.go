@@ -0,0 +1,96 @@
+package prefixmap
+
+import (
+  "errors"
+  "fmt"
+  "strings"
+)
+
+// ErrNoMatch is returned by Resolve when
+// no key starts with the abbreviation
+var ErrNoMatch = errors.New("prefixmap: no key matches the abbreviation")
+
+// AmbiguousError is returned by Resolve when
+// several keys start with the abbreviation
+type AmbiguousError struct {
+  Abbrev     string
+  Candidates []string // the keys starting with Abbrev, sorted
+}
+
+func (e *AmbiguousError) Error() string {
+  return fmt.Sprintf("prefixmap: ambiguous abbreviation %q: %s", e.Abbrev, strings.Join(e.Candidates, ", "))
+}
+
+// Resolve returns the key the given abbreviation stands for,
+// along with its values: the abbreviation itself if it is a key,
+// or else the only key starting with it.
+// Returns ErrNoMatch if no key starts with the abbreviation, or
+// an *AmbiguousError if several keys do.
+func (m *PrefixMap[V]) Resolve(abbrev string) (key string, values []V, err error) {
+  mNode := (*Node[V])(m)
+  normalized := m.opts.normalize(abbrev)
+  node := mNode.nodeForPrefix(normalized)
+  switch {
+  case node == nil || node.count == 0:
+    return "", nil, ErrNoMatch
+  case node.count > 1:
+    if node, exact := mNode.nodeForKey(normalized, false); exact && node.isKey {
+      return node.keyOf(normalized), node.data, nil
+    }
+
+    candidates := []string{}
+    m.AscendPrefix(abbrev, func(key string, _ []V) bool {
+      candidates = append(candidates, key)
+      return true
+    })
+    return "", nil, &AmbiguousError{Abbrev: abbrev, Candidates: candidates}
+  }
+
+  // the only key is the one of node or of one of its descendants
+  for !node.isKey {
+    for _, c := range node.Children {
+      if c.count > 0 {
+        node = c
+        break
+      }
+    }
+  }
+
+  return node.keyOf(node.Key()), node.data, nil
+}
+
+// ShortestUniquePrefixes returns the shortest abbreviation
+// Resolve resolves to each key in the map, by key.
+// The abbreviation of a key being a prefix of other keys
+// is the key itself. Abbreviations are normalized if the
+// map normalizes keys.
+func (m *PrefixMap[V]) ShortestUniquePrefixes() map[string]string {
+  prefixes := make(map[string]string, m.count)
+  mNode := (*Node[V])(m)
+  mNode.shortestUniquePrefixes("", "", prefixes)
+  return prefixes
+}
+
+// shortestUniquePrefixes adds to prefixes the shortest unique
+// prefixes of the keys in the subtree rooted at m, key being
+// the one of m and unique the shortest unique prefix of key,
+// if any
+func (m *Node[V]) shortestUniquePrefixes(key, unique string, prefixes map[string]string) {
+  if m.isKey {
+    if unique == "" {
+      prefixes[m.keyOf(key)] = key
+    } else {
+      prefixes[m.keyOf(key)] = unique
+    }
+  }
+
+  for _, c := range m.Children {
+    childUnique := unique
+    if unique == "" && c.count == 1 {
+      // the first unit of the child key is
+      // enough to tell its key apart
+      childUnique = key + c.key[:m.opts.firstLen(c.key)]
+    }
+    c.shortestUniquePrefixes(key+c.key, childUnique, prefixes)
+  }
+}
@@ -0,0 +1,104 @@
+package prefixmap
+
+import (
+  "errors"
+  "testing"
+)
+
+var resolveCommands = []string{"status", "stash", "show", "st", "commit", "checkout", "cherry-pick"}
+
+func TestResolve(t *testing.T) {
+  m := NewOf[string]()
+  for _, command := range resolveCommands {
+    m.Insert(command, command)
+  }
+
+  testCases := []struct {
+    abbrev     string
+    key        string
+    candidates []string
+  }{
+    {"stat", "status", nil},
+    {"status", "status", nil},
+    {"st", "st", nil},
+    {"sh", "show", nil},
+    {"co", "commit", nil},
+    {"cherry", "cherry-pick", nil},
+    {"s", "", []string{"show", "st", "stash", "status"}},
+    {"sta", "", []string{"stash", "status"}},
+    {"ch", "", []string{"checkout", "cherry-pick"}},
+    {"", "", []string{"checkout", "cherry-pick", "commit", "show", "st", "stash", "status"}},
+  }
+
+  for _, tc := range testCases {
+    key, values, err := m.Resolve(tc.abbrev)
+    if tc.candidates == nil {
+      if err != nil || key != tc.key || values[0] != tc.key {
+        t.Errorf("Unexpected resolution of '%s': got ('%s', %v, %v), expected '%s'", tc.abbrev, key, values, err, tc.key)
+      }
+      continue
+    }
+
+    var ambiguous *AmbiguousError
+    if !errors.As(err, &ambiguous) {
+      t.Errorf("Expected '%s' to be ambiguous, got ('%s', %v)", tc.abbrev, key, err)
+      continue
+    }
+    if ambiguous.Abbrev != tc.abbrev || !testStringsEq(ambiguous.Candidates, tc.candidates) {
+      t.Errorf("Unexpected candidates for '%s': got %v, expected %v", tc.abbrev, ambiguous.Candidates, tc.candidates)
+    }
+  }
+
+  for _, abbrev := range []string{"x", "stashes", "commits"} {
+    if _, _, err := m.Resolve(abbrev); err != ErrNoMatch {
+      t.Errorf("Expected no match for '%s', got %v", abbrev, err)
+    }
+  }
+  if _, _, err := NewOf[string]().Resolve(""); err != ErrNoMatch {
+    t.Errorf("Expected no match in an empty map, got %v", err)
+  }
+}
+
+func TestShortestUniquePrefixes(t *testing.T) {
+  m := NewOf[string]()
+  for _, command := range resolveCommands {
+    m.Insert(command, command)
+  }
+  m.Delete("show")
+
+  expected := map[string]string{
+    "status":      "stat",
+    "stash":       "stas",
+    "st":          "st",
+    "commit":      "co",
+    "checkout":    "chec",
+    "cherry-pick": "cher",
+  }
+  prefixes := m.ShortestUniquePrefixes()
+  if len(prefixes) != len(expected) {
+    t.Errorf("Unexpected prefixes: got %v, expected %v", prefixes, expected)
+  }
+  for key, prefix := range expected {
+    if prefixes[key] != prefix {
+      t.Errorf("Unexpected prefix for '%s': got '%s', expected '%s'", key, prefixes[key], prefix)
+    }
+    if resolved, _, err := m.Resolve(prefix); err != nil || resolved != key {
+      t.Errorf("Expected '%s' to resolve to '%s', got ('%s', %v)", prefix, key, resolved, err)
+    }
+    if resolved, _, _ := m.Resolve(prefix[:len(prefix)-1]); resolved == key {
+      t.Errorf("Expected '%s' not to be the shortest prefix of '%s'", prefix, key)
+    }
+  }
+
+  m = NewOf[string](RuneAware())
+  m.Insert("città", "città")
+  m.Insert("citrus", "citrus")
+  prefixes = m.ShortestUniquePrefixes()
+  if prefixes["città"] != "citt" || prefixes["citrus"] != "citr" {
+    t.Errorf("Unexpected prefixes: %v", prefixes)
+  }
+  m.Insert("cittá", "cittá")
+  if prefixes = m.ShortestUniquePrefixes(); prefixes["città"] != "città" {
+    t.Errorf("Expected rune-aware prefixes: got %v", prefixes)
+  }
+}
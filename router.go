@@ -0,0 +1,217 @@
+package prefixmap
+
+import (
+  "errors"
+  "fmt"
+  "strings"
+)
+
+var (
+  // ErrInvalidPattern is returned by Handle when the route pattern is malformed
+  ErrInvalidPattern = errors.New("prefixmap: invalid route pattern")
+
+  // ErrConflict is returned by Handle when the route pattern
+  // matches the same paths as a registered one
+  ErrConflict = errors.New("prefixmap: conflicting route pattern")
+)
+
+// Router maps HTTP-style path patterns to values.
+// Patterns are made of segments separated by '/' and may contain
+// named parameters, matching a single non-empty segment, and a
+// trailing catch-all parameter, matching the rest of the path:
+//  /users/:id/posts/*rest
+// When several patterns match a path, static segments take priority
+// over parameters, which take priority over catch-all parameters.
+type Router[V any] struct {
+  // patterns are stored with parameter names
+  // omitted, as in /users/:/posts/*
+  routes *PrefixMap[*route[V]]
+}
+
+// route is a registered pattern along with its values
+type route[V any] struct {
+  pattern string
+  params  []string
+  values  []V
+}
+
+// Param is a path parameter extracted by a Router
+type Param struct {
+  Key   string
+  Value string
+}
+
+// Params is the list of parameters extracted by a Router, in
+// the order they appear in the pattern
+type Params []Param
+
+// Get returns the value of the parameter with the
+// given name, or the empty string if no such parameter exists
+func (p Params) Get(name string) string {
+  for _, param := range p {
+    if param.Key == name {
+      return param.Value
+    }
+  }
+  return ""
+}
+
+// NewRouter returns a new empty router
+func NewRouter[V any]() *Router[V] {
+  return &Router[V]{
+    routes: NewOf[*route[V]](),
+  }
+}
+
+// Handle registers values for the given pattern, appending
+// them to the existing ones if the pattern is already registered.
+// Returns an error wrapping ErrInvalidPattern if the pattern is
+// malformed or ErrConflict if it only differs from a registered
+// pattern in its parameter names.
+func (r *Router[V]) Handle(pattern string, values ...V) error {
+  key, params, err := parseRoute(pattern)
+  if err != nil {
+    return err
+  }
+
+  if existing := r.routes.Get(key); existing != nil {
+    rt := existing[0]
+    if rt.pattern != pattern {
+      return fmt.Errorf("%w: %s conflicts with %s", ErrConflict, pattern, rt.pattern)
+    }
+    rt.values = append(rt.values, values...)
+    return nil
+  }
+
+  r.routes.Insert(key, &route[V]{
+    pattern: pattern,
+    params:  params,
+    values:  values,
+  })
+  return nil
+}
+
+// Lookup returns the values of the pattern matching the given path
+// along with the parameters extracted from it.
+// ok is false if no pattern matches the path.
+func (r *Router[V]) Lookup(path string) (values []V, params Params, ok bool) {
+  l := &routeLookup[V]{path: path}
+  if !l.walk((*Node[*route[V]])(r.routes), 0, 0, false) {
+    return nil, nil, false
+  }
+
+  rt := l.route
+  if len(rt.params) > 0 {
+    params = make(Params, len(rt.params))
+    for i, name := range rt.params {
+      params[i] = Param{Key: name, Value: l.params[i]}
+    }
+  }
+
+  return rt.values, params, true
+}
+
+// parseRoute validates the given pattern and returns the key
+// it is stored at, along with the names of its parameters
+func parseRoute(pattern string) (key string, params []string, err error) {
+  if !strings.HasPrefix(pattern, "/") {
+    return "", nil, fmt.Errorf("%w: %s does not start with '/'", ErrInvalidPattern, pattern)
+  }
+
+  segments := strings.Split(pattern[1:], "/")
+  for i, segment := range segments {
+    if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+      continue
+    }
+
+    name := segment[1:]
+    if name == "" {
+      return "", nil, fmt.Errorf("%w: unnamed parameter in %s", ErrInvalidPattern, pattern)
+    }
+    if segment[0] == '*' && i != len(segments)-1 {
+      return "", nil, fmt.Errorf("%w: catch-all parameter %s is not at the end of %s", ErrInvalidPattern, segment, pattern)
+    }
+    for _, param := range params {
+      if param == name {
+        return "", nil, fmt.Errorf("%w: duplicate parameter %s in %s", ErrInvalidPattern, name, pattern)
+      }
+    }
+
+    params = append(params, name)
+    segments[i] = segment[:1]
+  }
+
+  return "/" + strings.Join(segments, "/"), params, nil
+}
+
+// routeLookup holds the state of a path lookup
+type routeLookup[V any] struct {
+  path   string
+  params []string // the parameter values extracted so far
+  route  *route[V]
+}
+
+// walk matches the patterns continuing from the given offset within
+// the key of node against the path from pos onwards, backtracking to
+// lower priority segments on failure.
+// level tells if the offset is at the start of a pattern segment.
+// Returns true if a pattern matches.
+func (l *routeLookup[V]) walk(node *Node[*route[V]], offset, pos int, level bool) (found bool) {
+  depth := len(l.params)
+  defer func() {
+    if !found {
+      l.params = l.params[:depth]
+    }
+  }()
+
+  for ; offset < len(node.key); offset++ {
+    b := node.key[offset]
+    switch {
+    case level && b == ':':
+      end := len(l.path)
+      if i := strings.IndexByte(l.path[pos:], '/'); i >= 0 {
+        end = pos + i
+      }
+      if end == pos {
+        return false
+      }
+      l.params = append(l.params, l.path[pos:end])
+      pos = end
+    case level && b == '*':
+      l.params = append(l.params, l.path[pos:])
+      pos = len(l.path)
+    case pos < len(l.path) && l.path[pos] == b:
+      pos++
+    default:
+      return false
+    }
+    level = b == '/'
+  }
+
+  if pos == len(l.path) && node.isKey {
+    l.route = node.data[0]
+    return true
+  }
+
+  if pos < len(l.path) {
+    c := node.childFor(l.path[pos:])
+    if c != nil && !(level && isRouteParam(c.key[0])) && l.walk(c, 0, pos, level) {
+      return true
+    }
+  }
+  if level {
+    for _, param := range []string{":", "*"} {
+      if c := node.childFor(param); c != nil && l.walk(c, 0, pos, level) {
+        return true
+      }
+    }
+  }
+
+  return false
+}
+
+// isRouteParam tells if a pattern segment
+// starting with b is a parameter
+func isRouteParam(b byte) bool {
+  return b == ':' || b == '*'
+}
@@ -0,0 +1,121 @@
+package prefixmap
+
+import (
+  "errors"
+  "testing"
+)
+
+func TestRouterLookup(t *testing.T) {
+  r := NewRouter[string]()
+  for _, pattern := range []string{
+    "/",
+    "/users",
+    "/users/new",
+    "/users/:id",
+    "/users/:id/posts",
+    "/users/:id/posts/:post",
+    "/users/new/posts/draft",
+    "/files/*path",
+    "/files/public/index.html",
+    "/users/*rest",
+  } {
+    if err := r.Handle(pattern, pattern); err != nil {
+      t.Fatalf("Unexpected error handling '%s': %v", pattern, err)
+    }
+  }
+
+  testCases := []struct {
+    path    string
+    pattern string
+    params  Params
+  }{
+    {"/", "/", nil},
+    {"/users", "/users", nil},
+    {"/users/new", "/users/new", nil},
+    {"/users/newer", "/users/:id", Params{{"id", "newer"}}},
+    {"/users/42", "/users/:id", Params{{"id", "42"}}},
+    {"/users/42/posts", "/users/:id/posts", Params{{"id", "42"}}},
+    {"/users/42/posts/7", "/users/:id/posts/:post", Params{{"id", "42"}, {"post", "7"}}},
+    // backtracks from the static segment "new"
+    {"/users/new/posts/7", "/users/:id/posts/:post", Params{{"id", "new"}, {"post", "7"}}},
+    {"/users/new/posts/draft", "/users/new/posts/draft", nil},
+    {"/users/42/comments", "/users/*rest", Params{{"rest", "42/comments"}}},
+    {"/users/", "/users/*rest", Params{{"rest", ""}}},
+    {"/files/public/index.html", "/files/public/index.html", nil},
+    {"/files/public/style.css", "/files/*path", Params{{"path", "public/style.css"}}},
+  }
+
+  for _, tc := range testCases {
+    values, params, ok := r.Lookup(tc.path)
+    if !ok {
+      t.Errorf("Expected a pattern to match '%s'", tc.path)
+      continue
+    }
+    if len(values) != 1 || values[0] != tc.pattern {
+      t.Errorf("Unexpected pattern matching '%s': got %v, expected %s", tc.path, values, tc.pattern)
+    }
+    if len(params) != len(tc.params) {
+      t.Errorf("Unexpected params for '%s': got %v, expected %v", tc.path, params, tc.params)
+      continue
+    }
+    for i := range params {
+      if params[i] != tc.params[i] {
+        t.Errorf("Unexpected params for '%s': got %v, expected %v", tc.path, params, tc.params)
+      }
+    }
+  }
+
+  for _, path := range []string{"", "/files", "/user", "/posts", "users"} {
+    if values, _, ok := r.Lookup(path); ok {
+      t.Errorf("Unexpected match for '%s': %v", path, values)
+    }
+  }
+}
+
+func TestRouterParams(t *testing.T) {
+  r := NewRouter[int]()
+  r.Handle("/repos/:owner/:repo/issues/:number", 1)
+
+  _, params, ok := r.Lookup("/repos/alediaferia/prefixmap/issues/3")
+  if !ok {
+    t.Fatalf("Expected a pattern to match")
+  }
+  if params.Get("owner") != "alediaferia" || params.Get("repo") != "prefixmap" || params.Get("number") != "3" {
+    t.Errorf("Unexpected params: %v", params)
+  }
+  if params.Get("missing") != "" {
+    t.Errorf("Expected no value for a missing param")
+  }
+}
+
+func TestRouterHandleErrors(t *testing.T) {
+  r := NewRouter[int]()
+  r.Handle("/users/:id", 1)
+  r.Handle("/files/*path", 1)
+
+  testCases := []struct {
+    pattern string
+    err     error
+  }{
+    {"users", ErrInvalidPattern},
+    {"/users/:", ErrInvalidPattern},
+    {"/files/*", ErrInvalidPattern},
+    {"/files/*path/raw", ErrInvalidPattern},
+    {"/users/:id/:id", ErrInvalidPattern},
+    {"/users/:name", ErrConflict},
+    {"/files/*name", ErrConflict},
+    {"/users/:id", nil},
+    {"/users/:id/posts", nil},
+  }
+
+  for _, tc := range testCases {
+    if err := r.Handle(tc.pattern, 2); !errors.Is(err, tc.err) {
+      t.Errorf("Unexpected error handling '%s': got %v, expected %v", tc.pattern, err, tc.err)
+    }
+  }
+
+  values, _, _ := r.Lookup("/users/42")
+  if !testIntsEq(values, []int{1, 2}) {
+    t.Errorf("Expected values to be appended: got %v", values)
+  }
+}
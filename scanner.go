@@ -0,0 +1,206 @@
+package prefixmap
+
+import (
+  "io"
+)
+
+// ScanCallback is invoked by Scan for each occurrence of a key
+// in the text, text[start:end] being the occurrence.
+// Returning false stops the scanning.
+type ScanCallback[V any] func(start, end int, key string, values []V) bool
+
+// Scanner finds the occurrences of a set of keys in a text
+// in a single pass, using the Aho-Corasick automaton built by
+// Compile from the keys of a map
+type Scanner[V any] struct {
+  states []scanState
+
+  // the keys matched by the states
+  matches []scanMatch[V]
+}
+
+// scanState is a state of the automaton, reached
+// after reading the bytes of a key prefix
+type scanState struct {
+  edges []scanEdge // sorted by byte
+
+  // the state of the longest proper suffix of
+  // the state prefix being a key prefix too
+  fail int32
+
+  match int32 // the key the state matches, -1 if none
+
+  // the nearest state along the failure
+  // links matching a key, -1 if none
+  dict int32
+}
+
+type scanEdge struct {
+  b  byte
+  to int32
+}
+
+type scanMatch[V any] struct {
+  key    string
+  length int
+  values []V
+}
+
+// Compile builds a Scanner finding the occurrences of the keys
+// of the map in a text. The keys are matched as stored, that is
+// normalized if the map normalizes them, and the empty key is
+// never matched.
+// The scanner is not affected by later changes to the map.
+func (m *PrefixMap[V]) Compile() *Scanner[V] {
+  s := &Scanner[V]{
+    states: []scanState{{match: -1, dict: -1}},
+  }
+
+  mNode := (*Node[V])(m)
+  for _, c := range mNode.Children {
+    s.add(c, 0, c.key)
+  }
+  s.link()
+
+  return s
+}
+
+// add adds the states for the keys in the subtree
+// rooted at node, following the given state
+func (s *Scanner[V]) add(node *Node[V], state int32, key string) {
+  for i := 0; i < len(node.key); i++ {
+    // in a rune-aware map sibling keys may start with the
+    // same byte, sharing the state it leads to
+    if next, ok := s.edge(state, node.key[i]); ok {
+      state = next
+      continue
+    }
+    next := int32(len(s.states))
+    s.states = append(s.states, scanState{match: -1, dict: -1})
+    s.states[state].edges = append(s.states[state].edges, scanEdge{node.key[i], next})
+    state = next
+  }
+
+  if node.isKey {
+    s.states[state].match = int32(len(s.matches))
+    s.matches = append(s.matches, scanMatch[V]{
+      key:    node.keyOf(key),
+      length: len(key),
+      values: node.data,
+    })
+  }
+
+  for _, c := range node.Children {
+    s.add(c, state, key+c.key)
+  }
+}
+
+// link computes the failure and dictionary links
+// of the states, in breadth first order
+func (s *Scanner[V]) link() {
+  queue := []int32{0}
+  for len(queue) > 0 {
+    state := queue[0]
+    queue = queue[1:]
+
+    for _, edge := range s.states[state].edges {
+      next := &s.states[edge.to]
+      if state != 0 {
+        next.fail = s.step(s.states[state].fail, edge.b)
+      }
+
+      fail := &s.states[next.fail]
+      if fail.match >= 0 {
+        next.dict = next.fail
+      } else {
+        next.dict = fail.dict
+      }
+      queue = append(queue, edge.to)
+    }
+  }
+}
+
+// edge returns the state the edge of the given
+// one labeled with b leads to, if any
+func (s *Scanner[V]) edge(state int32, b byte) (int32, bool) {
+  edges := s.states[state].edges
+  i, j := 0, len(edges)
+  for i < j {
+    h := int(uint(i+j) >> 1)
+    if edges[h].b < b {
+      i = h + 1
+    } else {
+      j = h
+    }
+  }
+  if i < len(edges) && edges[i].b == b {
+    return edges[i].to, true
+  }
+  return 0, false
+}
+
+// step returns the state reached from the given one reading b
+func (s *Scanner[V]) step(state int32, b byte) int32 {
+  for {
+    if next, ok := s.edge(state, b); ok {
+      return next
+    }
+    if state == 0 {
+      return 0
+    }
+    state = s.states[state].fail
+  }
+}
+
+// Scan invokes callback for each occurrence of a key in text,
+// in order of occurrence end and then from the longest to the
+// shortest occurrence. Overlapping occurrences are all reported.
+func (s *Scanner[V]) Scan(text string, callback ScanCallback[V]) {
+  scan(s, 0, 0, text, callback)
+}
+
+// ScanReader is like Scan, reading the text from r.
+// Returns the error reading from r, if any other than io.EOF.
+func (s *Scanner[V]) ScanReader(r io.Reader, callback ScanCallback[V]) error {
+  buf := make([]byte, 32*1024)
+  state, offset := int32(0), 0
+  for {
+    n, err := r.Read(buf)
+    if n > 0 {
+      var ok bool
+      if state, ok = scan(s, state, offset, buf[:n], callback); !ok {
+        return nil
+      }
+      offset += n
+    }
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      return err
+    }
+  }
+}
+
+// scan feeds text to the automaton from the given state, text
+// starting at the given offset of the whole text scanned.
+// Returns the state reached and false if callback stopped the scan.
+func scan[V any, T string | []byte](s *Scanner[V], state int32, offset int, text T, callback ScanCallback[V]) (int32, bool) {
+  for i := 0; i < len(text); i++ {
+    state = s.step(state, text[i])
+
+    end := offset + i + 1
+    next := state
+    if s.states[next].match < 0 {
+      next = s.states[next].dict
+    }
+    for ; next >= 0; next = s.states[next].dict {
+      m := &s.matches[s.states[next].match]
+      if !callback(end-m.length, end, m.key, m.values) {
+        return state, false
+      }
+    }
+  }
+
+  return state, true
+}
@@ -0,0 +1,142 @@
+package prefixmap
+
+import (
+  "errors"
+  "strings"
+  "testing"
+  "testing/iotest"
+)
+
+type scanOccurrence struct {
+  start, end int
+  key        string
+}
+
+func testScan(t *testing.T, scanner *Scanner[string], text string) []scanOccurrence {
+  occurrences := []scanOccurrence{}
+  scanner.Scan(text, func(start, end int, key string, values []string) bool {
+    if values[0] != key || text[start:end] != key {
+      t.Errorf("Unexpected occurrence of '%s' at [%d, %d) in '%s'", key, start, end, text)
+    }
+    occurrences = append(occurrences, scanOccurrence{start, end, key})
+    return true
+  })
+  return occurrences
+}
+
+// naiveScan looks for each key at each position of text,
+// reporting the occurrences in the order Scan does
+func naiveScan(keys []string, text string) []scanOccurrence {
+  occurrences := []scanOccurrence{}
+  for end := 1; end <= len(text); end++ {
+    for start := 0; start < end; start++ {
+      for _, key := range keys {
+        if text[start:end] == key {
+          occurrences = append(occurrences, scanOccurrence{start, end, key})
+        }
+      }
+    }
+  }
+  return occurrences
+}
+
+func testOccurrencesEq(a, b []scanOccurrence) bool {
+  if len(a) != len(b) {
+    return false
+  }
+  for i := range a {
+    if a[i] != b[i] {
+      return false
+    }
+  }
+  return true
+}
+
+func TestScan(t *testing.T) {
+  testCases := []struct {
+    keys  []string
+    texts []string
+    opts  []Option
+  }{
+    {[]string{"he", "she", "his", "hers"}, []string{"ushers", "ahishers", "", "xyz"}, nil},
+    {[]string{"a", "aa", "aaa"}, []string{"aaaa", "baab"}, nil},
+    {nodeTests[0].words, []string{"romanus romulus rubicundus", "Arubiconrube", "rrromanee"}, nil},
+    {[]string{"abcd", "bc", "bcde", "c"}, []string{"abcde", "xbcdx"}, nil},
+    {[]string{"café", "cafè", "è", "é"}, []string{"un café et un cafè", "cafécafè"}, []Option{RuneAware()}},
+  }
+
+  for _, tc := range testCases {
+    m := NewOf[string](tc.opts...)
+    for _, key := range tc.keys {
+      m.Insert(key, key)
+    }
+    scanner := m.Compile()
+
+    for _, text := range tc.texts {
+      occurrences := testScan(t, scanner, text)
+      if expected := naiveScan(tc.keys, text); !testOccurrencesEq(occurrences, expected) {
+        t.Errorf("Unexpected occurrences in '%s': got %v, expected %v", text, occurrences, expected)
+      }
+    }
+  }
+}
+
+func TestScanStop(t *testing.T) {
+  m := NewOf[string]()
+  m.Insert("a", "a")
+  m.Insert("", "empty")
+
+  count := 0
+  m.Compile().Scan("aaaa", func(start, end int, key string, values []string) bool {
+    count++
+    return count < 2
+  })
+  if count != 2 {
+    t.Errorf("Expected the scan to stop after 2 occurrences, got %d", count)
+  }
+}
+
+func TestScanNormalized(t *testing.T) {
+  m := NewOf[string](WithNormalizer(ASCIIFold))
+  m.Insert("Rome", "Rome")
+
+  occurrences := []string{}
+  m.Compile().Scan("ROME rome Rome", func(start, end int, key string, values []string) bool {
+    occurrences = append(occurrences, key)
+    return true
+  })
+  if !testStringsEq(occurrences, []string{"Rome"}) {
+    t.Errorf("Expected keys to be matched as stored: got %v", occurrences)
+  }
+}
+
+func TestScanReader(t *testing.T) {
+  m := NewOf[string]()
+  for _, key := range []string{"he", "she", "his", "hers"} {
+    m.Insert(key, key)
+  }
+  scanner := m.Compile()
+
+  text := strings.Repeat("ushers ahishers ", 5000)
+  expected := testScan(t, scanner, text)
+
+  occurrences := []scanOccurrence{}
+  err := scanner.ScanReader(iotest.HalfReader(strings.NewReader(text)), func(start, end int, key string, values []string) bool {
+    occurrences = append(occurrences, scanOccurrence{start, end, key})
+    return true
+  })
+  if err != nil {
+    t.Errorf("Unexpected error: %v", err)
+  }
+  if !testOccurrencesEq(occurrences, expected) {
+    t.Errorf("Unexpected occurrences reading the text: got %d, expected %d", len(occurrences), len(expected))
+  }
+
+  errRead := errors.New("read error")
+  err = scanner.ScanReader(iotest.ErrReader(errRead), func(int, int, string, []string) bool {
+    return true
+  })
+  if err != errRead {
+    t.Errorf("Expected the read error to be returned, got %v", err)
+  }
+}
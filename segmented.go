@@ -0,0 +1,292 @@
+package prefixmap
+
+import (
+  "iter"
+  "strings"
+)
+
+// SegmentedPrefixMap is a map whose keys are made of segments
+// separated by a separator, as in "a/b/c" or "com.example.svc".
+// Prefix queries respect segment boundaries: the prefix "a/b"
+// matches "a/b" and "a/b/c" but not "a/bc".
+type SegmentedPrefixMap[V any] struct {
+  m   *PrefixMap[V]
+  sep string
+}
+
+// NewSegmented returns a new empty map whose keys are
+// made of segments separated by the given separator,
+// configured with the given options
+func NewSegmented[V any](separator string, opts ...Option) *SegmentedPrefixMap[V] {
+  return &SegmentedPrefixMap[V]{
+    m:   NewOf[V](opts...),
+    sep: separator,
+  }
+}
+
+// Insert inserts new values in the map for the specified key,
+// appending them to the existing ones if any
+func (s *SegmentedPrefixMap[V]) Insert(key string, values ...V) {
+  s.m.Insert(key, values...)
+}
+
+// Replace replaces the value(s) for the given key in the map
+func (s *SegmentedPrefixMap[V]) Replace(key string, values ...V) {
+  s.m.Replace(key, values...)
+}
+
+// Get returns the data associated with the given key in the map
+// or nil if no such key is present in the map
+func (s *SegmentedPrefixMap[V]) Get(key string) []V {
+  return s.m.Get(key)
+}
+
+// Contains checks if the given key is present in the map
+func (s *SegmentedPrefixMap[V]) Contains(key string) bool {
+  return s.m.Contains(key)
+}
+
+// Delete removes the given key and its values from the map.
+// Returns false if no such key is present in the map.
+func (s *SegmentedPrefixMap[V]) Delete(key string) bool {
+  return s.m.Delete(key)
+}
+
+// Len returns the number of keys in the map
+func (s *SegmentedPrefixMap[V]) Len() int {
+  return s.m.Len()
+}
+
+// All returns an iterator over the keys in the map and
+// their values, in ascending lexicographic order
+func (s *SegmentedPrefixMap[V]) All() iter.Seq2[string, []V] {
+  return s.m.All()
+}
+
+// GetByPrefix returns a flattened collection of the values
+// associated with the given path and with the keys below it,
+// sorted by key
+func (s *SegmentedPrefixMap[V]) GetByPrefix(path string) []V {
+  if path == "" {
+    return s.m.GetByPrefix("")
+  }
+  values := append([]V{}, s.m.Get(path)...)
+  return append(values, s.m.GetByPrefix(path+s.sep)...)
+}
+
+// ContainsPrefix checks if the given path is
+// present in the map, as a key or as a prefix
+func (s *SegmentedPrefixMap[V]) ContainsPrefix(path string) bool {
+  if path == "" {
+    return s.m.ContainsPrefix("")
+  }
+  return s.m.Contains(path) || s.m.ContainsPrefix(path+s.sep)
+}
+
+// CountPrefix returns the number of keys in the
+// map equal to the given path or below it
+func (s *SegmentedPrefixMap[V]) CountPrefix(path string) int {
+  if path == "" {
+    return s.m.Len()
+  }
+  count := s.m.CountPrefix(path + s.sep)
+  if s.m.Contains(path) {
+    count++
+  }
+  return count
+}
+
+// DeletePrefix removes the given path and all the keys below
+// it from the map. Returns the number of keys removed.
+func (s *SegmentedPrefixMap[V]) DeletePrefix(path string) int {
+  if path == "" {
+    return s.m.DeletePrefix("")
+  }
+  count := s.m.DeletePrefix(path + s.sep)
+  if s.m.Delete(path) {
+    count++
+  }
+  return count
+}
+
+// LongestPrefix returns the longest key in the map made of
+// leading segments of the given input, along with its values.
+// ok is false if there's no such key.
+func (s *SegmentedPrefixMap[V]) LongestPrefix(input string) (key string, values []V, ok bool) {
+  mNode := (*Node[V])(s.m)
+  input = s.m.opts.normalize(input)
+  mNode.eachKeyPrefixOf(input, func(node *Node[V], length int) bool {
+    if length == 0 || length == len(input) || strings.HasPrefix(input[length:], s.sep) {
+      key, values, ok = node.keyOf(input[:length]), node.data, true
+    }
+    return true
+  })
+
+  return
+}
+
+// EachPrefix iterates over the prefixes in the map ending on a
+// segment boundary, in lexicographic order.
+// Skipping a prefix branch skips the keys below it, not the ones
+// extending its last segment: skipping "a/b" still visits "a/bc".
+// Keys starting with the separator have the empty prefix "".
+func (s *SegmentedPrefixMap[V]) EachPrefix(callback PrefixCallback[V]) {
+  w := &segmentWalk[V]{
+    sep: s.sep,
+    visit: func(node *Node[V], key []byte, isKey bool) (bool, bool) {
+      info := Prefix[V]{
+        node: node,
+        Key:  string(key),
+      }
+      if isKey {
+        info.Key = node.keyOf(info.Key)
+        info.Values = node.data
+      }
+      return callback(info)
+    },
+  }
+
+  mNode := (*Node[V])(s.m)
+  w.walkFrom(mNode, 0)
+}
+
+// Children returns the distinct segments immediately following the
+// given path in the map keys, in lexicographic order, as if listing
+// a directory. The empty path lists the first segments, while a path
+// ending with the separator lists the segments following it:
+// for "/usr/bin", "" lists the empty segment and "/" lists "usr".
+func (s *SegmentedPrefixMap[V]) Children(path string) []string {
+  children := []string{}
+  prefix := ""
+  if path != "" {
+    prefix = s.m.opts.normalize(path)
+    if !strings.HasSuffix(prefix, s.sep) {
+      prefix += s.sep
+    }
+  }
+
+  mNode := (*Node[V])(s.m)
+  node := mNode.nodeForPrefix(prefix)
+  if node == nil {
+    return children
+  }
+  // the position of the prefix end within node
+  offset := len(node.key) - (len(node.Key()) - len(prefix))
+
+  w := &segmentWalk[V]{
+    sep: s.sep,
+    key: []byte(prefix),
+    visit: func(node *Node[V], key []byte, isKey bool) (bool, bool) {
+      children = append(children, string(key[len(prefix):]))
+      return true, false
+    },
+  }
+  w.walkFrom(node, offset)
+
+  return children
+}
+
+// segmentWalk visits the positions of a subtree ending on
+// a segment boundary, that is the ones ending a key or followed
+// by the separator, in lexicographic order
+type segmentWalk[V any] struct {
+  sep  string
+  skip string // the prefix of the branch to skip, if any
+  key  []byte
+
+  // invoked for each position, returns whether to skip
+  // the keys below it and whether to halt the walk
+  visit func(node *Node[V], key []byte, isKey bool) (skipBranch bool, halt bool)
+}
+
+// walk visits the positions within the subtree rooted at node
+// following the given offset in its key.
+// Returns true if the walk has been halted.
+func (w *segmentWalk[V]) walk(node *Node[V], offset int) bool {
+  depth := len(w.key)
+  defer func() {
+    w.key = w.key[:depth]
+  }()
+
+  for i := offset; i < len(node.key); i++ {
+    w.key = append(w.key, node.key[i])
+    if string(w.key) == w.skip {
+      return false
+    }
+
+    isKey := i == len(node.key)-1 && node.isKey
+    if isKey || node.continuesWith(i+1, w.sep) {
+      skip, halt := w.visit(node, w.key, isKey)
+      if halt {
+        return true
+      }
+      if skip {
+        w.skip = string(w.key) + w.sep
+      }
+    }
+  }
+
+  for _, c := range node.Children {
+    if w.walk(c, 0) {
+      return true
+    }
+  }
+
+  return false
+}
+
+// walkFrom is like walk, but also visits the position at the given
+// offset, where an empty segment or the empty key may end.
+// Returns true if the walk has been halted.
+func (w *segmentWalk[V]) walkFrom(node *Node[V], offset int) bool {
+  isKey := offset == len(node.key) && node.isKey
+  if isKey || node.continuesWith(offset, w.sep) {
+    skip, halt := w.visit(node, w.key, isKey)
+    if halt {
+      return true
+    }
+    if skip {
+      w.skip = string(w.key) + w.sep
+    }
+  }
+
+  if offset < len(node.key) {
+    return w.walk(node, offset)
+  }
+  for _, c := range node.Children {
+    if w.walk(c, 0) {
+      return true
+    }
+  }
+
+  return false
+}
+
+// continuesWith tells if any key continues with s
+// from the given offset within the key of m
+func (m *Node[V]) continuesWith(offset int, s string) bool {
+  node, _ := m.advance(offset, s)
+  return node != nil
+}
+
+// advance follows s from the given offset within the key of m.
+// Returns the node and the offset within its key s ends at,
+// or a nil node if no key continues with s.
+func (m *Node[V]) advance(offset int, s string) (*Node[V], int) {
+  node := m
+  for len(s) > 0 {
+    if offset == len(node.key) {
+      if node = node.childFor(s); node == nil {
+        return nil, 0
+      }
+      offset = 0
+    }
+    n := min(len(s), len(node.key)-offset)
+    if node.key[offset:offset+n] != s[:n] {
+      return nil, 0
+    }
+    s, offset = s[n:], offset+n
+  }
+
+  return node, offset
+}
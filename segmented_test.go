@@ -0,0 +1,179 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+var segmentedKeys = []string{
+  "a",
+  "a/b",
+  "a/b/c",
+  "a/bc",
+  "a/bc/d",
+  "a/x/y/z",
+  "b",
+}
+
+func TestSegmentedPrefix(t *testing.T) {
+  s := NewSegmented[string]("/")
+  for _, key := range segmentedKeys {
+    s.Insert(key, key)
+  }
+
+  testCases := []struct {
+    path     string
+    expected []string
+  }{
+    {"a/b", []string{"a/b", "a/b/c"}},
+    {"a/bc", []string{"a/bc", "a/bc/d"}},
+    {"a/x", []string{"a/x/y/z"}},
+    {"a/x/y/", []string{}},
+    {"a/", []string{}},
+    {"c", []string{}},
+  }
+
+  for _, tc := range testCases {
+    values := s.GetByPrefix(tc.path)
+    if !testStringsEq(values, tc.expected) {
+      t.Errorf("Unexpected values for path '%s': got %v, expected %v", tc.path, values, tc.expected)
+    }
+    if count := s.CountPrefix(tc.path); count != len(tc.expected) {
+      t.Errorf("Unexpected count for path '%s': got %d, expected %d", tc.path, count, len(tc.expected))
+    }
+    if contains := s.ContainsPrefix(tc.path); contains != (len(tc.expected) > 0) {
+      t.Errorf("Unexpected result for path '%s': got %v", tc.path, contains)
+    }
+  }
+
+  if deleted := s.DeletePrefix("a/b"); deleted != 2 {
+    t.Errorf("Unexpected number of deleted keys: got %d, expected %d", deleted, 2)
+  }
+  if !s.Contains("a/bc") || s.Contains("a/b/c") {
+    t.Errorf("Only keys below 'a/b' are expected to be deleted")
+  }
+}
+
+func TestSegmentedLongestPrefix(t *testing.T) {
+  s := NewSegmented[string](".")
+  for _, key := range []string{"com", "com.example", "com.exam"} {
+    s.Insert(key, key)
+  }
+
+  testCases := []struct {
+    input, key string
+    ok         bool
+  }{
+    {"com.example.svc", "com.example", true},
+    {"com.example", "com.example", true},
+    {"com.examples", "com", true},
+    {"com.exa", "com", true},
+    {"org.example", "", false},
+  }
+
+  for _, tc := range testCases {
+    key, _, ok := s.LongestPrefix(tc.input)
+    if key != tc.key || ok != tc.ok {
+      t.Errorf("Unexpected longest prefix of '%s': got ('%s', %v), expected ('%s', %v)", tc.input, key, ok, tc.key, tc.ok)
+    }
+  }
+}
+
+func TestSegmentedEachPrefix(t *testing.T) {
+  s := NewSegmented[string]("/")
+  for _, key := range segmentedKeys {
+    s.Insert(key, key)
+  }
+
+  prefixes := []string{}
+  s.EachPrefix(func(prefix Prefix[string]) (bool, bool) {
+    prefixes = append(prefixes, prefix.Key)
+    if prefix.Key == "a/x" {
+      return true, false
+    }
+    return false, false
+  })
+  expected := []string{"a", "a/b", "a/b/c", "a/bc", "a/bc/d", "a/x", "b"}
+  if !testStringsEq(prefixes, expected) {
+    t.Errorf("Unexpected prefixes: got %v, expected %v", prefixes, expected)
+  }
+
+  prefixes = []string{}
+  s.EachPrefix(func(prefix Prefix[string]) (bool, bool) {
+    prefixes = append(prefixes, prefix.Key)
+    return prefix.Key == "a/b", prefix.Key == "a/bc/d"
+  })
+  expected = []string{"a", "a/b", "a/bc", "a/bc/d"}
+  if !testStringsEq(prefixes, expected) {
+    t.Errorf("Unexpected prefixes: got %v, expected %v", prefixes, expected)
+  }
+}
+
+func TestSegmentedChildren(t *testing.T) {
+  s := NewSegmented[string]("/")
+  for _, key := range segmentedKeys {
+    s.Insert(key, key)
+  }
+
+  testCases := []struct {
+    path     string
+    expected []string
+  }{
+    {"", []string{"a", "b"}},
+    {"a", []string{"b", "bc", "x"}},
+    {"a/b", []string{"c"}},
+    {"a/x", []string{"y"}},
+    {"a/b/c", []string{}},
+    {"z", []string{}},
+  }
+
+  for _, tc := range testCases {
+    if children := s.Children(tc.path); !testStringsEq(children, tc.expected) {
+      t.Errorf("Unexpected children of '%s': got %v, expected %v", tc.path, children, tc.expected)
+    }
+  }
+}
+
+func TestSegmentedEmptySegments(t *testing.T) {
+  s := NewSegmented[string]("/")
+  for _, key := range []string{"/usr/bin", "/usr/lib", "b//cb", "b/c"} {
+    s.Insert(key, key)
+  }
+
+  testCases := []struct {
+    path     string
+    expected []string
+  }{
+    {"", []string{"", "b"}},
+    {"/", []string{"usr"}},
+    {"/usr", []string{"bin", "lib"}},
+    {"b", []string{"", "c"}},
+    {"b/", []string{"", "c"}},
+    {"b//", []string{"cb"}},
+  }
+
+  for _, tc := range testCases {
+    if children := s.Children(tc.path); !testStringsEq(children, tc.expected) {
+      t.Errorf("Unexpected children of '%s': got %v, expected %v", tc.path, children, tc.expected)
+    }
+  }
+
+  prefixes := []string{}
+  s.EachPrefix(func(prefix Prefix[string]) (bool, bool) {
+    prefixes = append(prefixes, prefix.Key)
+    return false, false
+  })
+  expected := []string{"", "/usr", "/usr/bin", "/usr/lib", "b", "b/", "b//cb", "b/c"}
+  if !testStringsEq(prefixes, expected) {
+    t.Errorf("Unexpected prefixes: got %v, expected %v", prefixes, expected)
+  }
+
+  prefixes = []string{}
+  s.EachPrefix(func(prefix Prefix[string]) (bool, bool) {
+    prefixes = append(prefixes, prefix.Key)
+    return prefix.Key == "" || prefix.Key == "b/", false
+  })
+  expected = []string{"", "b", "b/", "b/c"}
+  if !testStringsEq(prefixes, expected) {
+    t.Errorf("Unexpected prefixes: got %v, expected %v", prefixes, expected)
+  }
+}
@@ -0,0 +1,130 @@
+package prefixmap
+
+import (
+  "sort"
+)
+
+// SuffixIndex maps keys to values like PrefixMap, also answering
+// "ends with" and "contains" queries.
+// Every suffix of each key, starting at a rune boundary, is indexed
+// in a PrefixMap mapping it to the keys ending with it: the keys
+// ending with a suffix are the ones it maps to, and the keys
+// containing a substring are the ones its extensions map to.
+// Indexing takes space quadratic in the length of the keys.
+type SuffixIndex[V any] struct {
+  keys     *PrefixMap[V]
+  suffixes *PrefixMap[string]
+}
+
+// NewSuffixIndex returns a new empty SuffixIndex
+func NewSuffixIndex[V any]() *SuffixIndex[V] {
+  return &SuffixIndex[V]{
+    keys:     NewOf[V](),
+    suffixes: NewOf[string](),
+  }
+}
+
+// Insert inserts new values in the index for the specified key,
+// appending them to the existing ones if any
+func (s *SuffixIndex[V]) Insert(key string, values ...V) {
+  if !s.keys.Contains(key) {
+    s.index(key)
+  }
+  s.keys.Insert(key, values...)
+}
+
+// Replace replaces the value(s) for the given key in the index
+func (s *SuffixIndex[V]) Replace(key string, values ...V) {
+  if !s.keys.Contains(key) {
+    s.index(key)
+  }
+  s.keys.Replace(key, values...)
+}
+
+// Get returns the data associated with the given key in the
+// index or nil if no such key is present in the index
+func (s *SuffixIndex[V]) Get(key string) []V {
+  return s.keys.Get(key)
+}
+
+// Contains checks if the given key is present in the index
+func (s *SuffixIndex[V]) Contains(key string) bool {
+  return s.keys.Contains(key)
+}
+
+// Len returns the number of keys in the index
+func (s *SuffixIndex[V]) Len() int {
+  return s.keys.Len()
+}
+
+// Delete removes the given key and its values from the index.
+// Returns false if no such key is present in the index.
+func (s *SuffixIndex[V]) Delete(key string) bool {
+  if !s.keys.Delete(key) {
+    return false
+  }
+
+  for i := range key {
+    suffix := key[i:]
+    keys := s.suffixes.Get(suffix)
+    remaining := make([]string, 0, len(keys)-1)
+    for _, k := range keys {
+      if k != key {
+        remaining = append(remaining, k)
+      }
+    }
+
+    if len(remaining) == 0 {
+      s.suffixes.Delete(suffix)
+    } else {
+      s.suffixes.Replace(suffix, remaining...)
+    }
+  }
+
+  return true
+}
+
+// GetBySuffix returns a flattened collection of the values
+// associated with the keys ending with the given suffix,
+// sorted by key
+func (s *SuffixIndex[V]) GetBySuffix(suffix string) []V {
+  if suffix == "" {
+    return s.keys.GetByPrefix("")
+  }
+  return s.valuesOf(s.suffixes.Get(suffix))
+}
+
+// GetBySubstring returns a flattened collection of the values
+// associated with the keys containing the given substring,
+// sorted by key. The values of each key are returned once
+// however many times the key contains the substring.
+func (s *SuffixIndex[V]) GetBySubstring(substring string) []V {
+  if substring == "" {
+    return s.keys.GetByPrefix("")
+  }
+  return s.valuesOf(s.suffixes.GetByPrefix(substring))
+}
+
+// index maps the suffixes of key to it
+func (s *SuffixIndex[V]) index(key string) {
+  for i := range key {
+    s.suffixes.Insert(key[i:], key)
+  }
+}
+
+// valuesOf returns the values of the given
+// keys, sorted by key and without duplicates
+func (s *SuffixIndex[V]) valuesOf(keys []string) []V {
+  keys = append([]string{}, keys...)
+  sort.Strings(keys)
+
+  values := []V{}
+  for i, key := range keys {
+    if i > 0 && keys[i-1] == key {
+      continue
+    }
+    values = append(values, s.keys.Get(key)...)
+  }
+
+  return values
+}
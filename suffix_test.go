@@ -0,0 +1,96 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestGetBySuffix(t *testing.T) {
+  s := NewSuffixIndex[string]()
+  for _, key := range nodeTests[0].words {
+    s.Insert(key, key)
+  }
+  s.Insert("banana", "banana")
+  s.Insert("perché", "perché")
+
+  testCases := []struct {
+    suffix   string
+    expected []string
+  }{
+    {"us", []string{"romanus", "romulus", "rubicundus"}},
+    {"ulus", []string{"romulus"}},
+    {"romulus", []string{"romulus"}},
+    {"ana", []string{"banana"}},
+    {"é", []string{"perché"}},
+    {"rom", []string{}},
+    {"xromulus", []string{}},
+  }
+
+  for _, tc := range testCases {
+    if values := s.GetBySuffix(tc.suffix); !testStringsEq(values, tc.expected) {
+      t.Errorf("Unexpected values for suffix '%s': got %v, expected %v", tc.suffix, values, tc.expected)
+    }
+  }
+
+  if values := s.GetBySuffix(""); len(values) != s.Len() {
+    t.Errorf("Expected all the values for the empty suffix: got %v", values)
+  }
+}
+
+func TestGetBySubstring(t *testing.T) {
+  s := NewSuffixIndex[string]()
+  for _, key := range nodeTests[0].words {
+    s.Insert(key, key)
+  }
+  s.Insert("banana", "banana")
+  s.Insert("perché", "perché")
+
+  testCases := []struct {
+    substring string
+    expected  []string
+  }{
+    {"ub", []string{"rubens", "ruber", "rubicon", "rubicundus"}},
+    {"an", []string{"banana", "romane", "romanus"}},
+    {"ana", []string{"banana"}},
+    {"bic", []string{"rubicon", "rubicundus"}},
+    {"rom", []string{"romane", "romanus", "romulus"}},
+    {"ch", []string{"perché"}},
+    {"hé", []string{"perché"}},
+    {"A", []string{"A"}},
+    {"x", []string{}},
+  }
+
+  for _, tc := range testCases {
+    if values := s.GetBySubstring(tc.substring); !testStringsEq(values, tc.expected) {
+      t.Errorf("Unexpected values for substring '%s': got %v, expected %v", tc.substring, values, tc.expected)
+    }
+  }
+}
+
+func TestSuffixIndexDelete(t *testing.T) {
+  s := NewSuffixIndex[string]()
+  for _, key := range nodeTests[0].words {
+    s.Insert(key, key)
+  }
+  s.Insert("banana", "banana")
+  s.Insert("perché", "perché")
+  s.Insert("banana", "plantain")
+  if values := s.GetBySubstring("nan"); !testStringsEq(values, []string{"banana", "plantain"}) {
+    t.Errorf("Expected values to be appended: got %v", values)
+  }
+
+  if !s.Delete("romanus") || s.Delete("romanus") {
+    t.Errorf("Expected 'romanus' to be deleted once")
+  }
+  if values := s.GetBySuffix("us"); !testStringsEq(values, []string{"romulus", "rubicundus"}) {
+    t.Errorf("Unexpected values after deletion: got %v", values)
+  }
+
+  for _, key := range nodeTests[0].words {
+    s.Delete(key)
+  }
+  s.Delete("banana")
+  s.Delete("perché")
+  if s.Len() != 0 || s.suffixes.Len() != 0 {
+    t.Errorf("Expected the index to be empty: got %d keys, %d suffixes", s.Len(), s.suffixes.Len())
+  }
+}
@@ -0,0 +1,389 @@
+package prefixmap
+
+import (
+  "iter"
+  "regexp/syntax"
+  "sync"
+)
+
+// SyncPrefixMap is a PrefixMap safe for concurrent use by
+// multiple goroutines, guarded by a read-write mutex: reads
+// proceed concurrently while writes are exclusive.
+//
+// Iterations, either through callbacks or iterators, hold the read
+// lock until they end, so that they see the map as it was when they
+// started. As read locks are not reentrant, no method of the map,
+// not even a read, may be called from within the callbacks or the
+// loop bodies, which could deadlock as soon as a writer is waiting:
+// use View to read the map while iterating over it.
+//
+// The values returned are shared with the map and must not be
+// modified, and the methods of the Prefix values returned must
+// not be called while the map is being written.
+// Cursors are not available: use View to walk the map with one.
+type SyncPrefixMap[V any] struct {
+  mu sync.RWMutex
+  m  *PrefixMap[V]
+}
+
+// NewSync returns a new empty SyncPrefixMap
+// configured with the given options
+func NewSync[V any](opts ...Option) *SyncPrefixMap[V] {
+  return &SyncPrefixMap[V]{
+    m: NewOf[V](opts...),
+  }
+}
+
+// View invokes fn with the underlying map while holding the
+// read lock, so that several reads see the same map.
+// fn must not write the map nor retain it.
+func (s *SyncPrefixMap[V]) View(fn func(m *PrefixMap[V])) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  fn(s.m)
+}
+
+// Update invokes fn with the underlying map while holding
+// the write lock, so that several writes happen atomically.
+// fn must not retain the map.
+func (s *SyncPrefixMap[V]) Update(fn func(m *PrefixMap[V])) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  fn(s.m)
+}
+
+// Insert inserts new values in the map for the specified key,
+// appending them to the existing ones if any
+func (s *SyncPrefixMap[V]) Insert(key string, values ...V) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  s.m.Insert(key, values...)
+}
+
+// InsertWeighted inserts new values in the map for the
+// specified key like Insert, setting the key weight
+func (s *SyncPrefixMap[V]) InsertWeighted(key string, weight float64, values ...V) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  s.m.InsertWeighted(key, weight, values...)
+}
+
+// Replace replaces the value(s) for the given key in the map
+func (s *SyncPrefixMap[V]) Replace(key string, values ...V) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  s.m.Replace(key, values...)
+}
+
+// Delete removes the given key and its values from the map.
+// Returns false if no such key is present in the map.
+func (s *SyncPrefixMap[V]) Delete(key string) bool {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  return s.m.Delete(key)
+}
+
+// DeletePrefix removes all the keys starting with the given
+// prefix from the map. Returns the number of keys removed.
+func (s *SyncPrefixMap[V]) DeletePrefix(prefix string) int {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  return s.m.DeletePrefix(prefix)
+}
+
+// Len returns the number of keys in the map
+func (s *SyncPrefixMap[V]) Len() int {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Len()
+}
+
+// CountPrefix returns the number of keys
+// starting with the given prefix
+func (s *SyncPrefixMap[V]) CountPrefix(prefix string) int {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.CountPrefix(prefix)
+}
+
+// Contains checks if the given key is present in the map
+func (s *SyncPrefixMap[V]) Contains(key string) bool {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Contains(key)
+}
+
+// Get returns the data associated with the given key in the map
+// or nil if no such key is present in the map
+func (s *SyncPrefixMap[V]) Get(key string) []V {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Get(key)
+}
+
+// GetByPrefix returns a flattened collection of values
+// associated with the given prefix key, sorted by key
+func (s *SyncPrefixMap[V]) GetByPrefix(key string) []V {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.GetByPrefix(key)
+}
+
+// ContainsPrefix checks if the given prefix is present as
+// key or as a prefix of a key in the map
+func (s *SyncPrefixMap[V]) ContainsPrefix(key string) bool {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.ContainsPrefix(key)
+}
+
+// LongestPrefix returns the longest key in the map that is
+// a prefix of the given input, along with its values
+func (s *SyncPrefixMap[V]) LongestPrefix(input string) (key string, values []V, ok bool) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.LongestPrefix(input)
+}
+
+// ShortestPrefix returns the shortest key in the map that is
+// a prefix of the given input, along with its values
+func (s *SyncPrefixMap[V]) ShortestPrefix(input string) (key string, values []V, ok bool) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.ShortestPrefix(input)
+}
+
+// PrefixesOf returns all the keys in the map that are
+// prefixes of the given input, from the shortest to the longest
+func (s *SyncPrefixMap[V]) PrefixesOf(input string) []Prefix[V] {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.PrefixesOf(input)
+}
+
+// EachPrefix iterates over the prefixes contained in the
+// map in lexicographic order, holding the read lock
+func (s *SyncPrefixMap[V]) EachPrefix(callback PrefixCallback[V]) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  s.m.EachPrefix(callback)
+}
+
+// Ascend iterates over the keys contained in the map in
+// ascending lexicographic order, holding the read lock
+func (s *SyncPrefixMap[V]) Ascend(callback KeyCallback[V]) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  s.m.Ascend(callback)
+}
+
+// Descend iterates over the keys contained in the map in
+// descending lexicographic order, holding the read lock
+func (s *SyncPrefixMap[V]) Descend(callback KeyCallback[V]) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  s.m.Descend(callback)
+}
+
+// AscendPrefix iterates over the keys starting with the given prefix
+// in ascending lexicographic order, holding the read lock
+func (s *SyncPrefixMap[V]) AscendPrefix(prefix string, callback KeyCallback[V]) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  s.m.AscendPrefix(prefix, callback)
+}
+
+// DescendPrefix iterates over the keys starting with the given prefix
+// in descending lexicographic order, holding the read lock
+func (s *SyncPrefixMap[V]) DescendPrefix(prefix string, callback KeyCallback[V]) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  s.m.DescendPrefix(prefix, callback)
+}
+
+// Range iterates over the keys in [from, to) in ascending
+// lexicographic order, holding the read lock
+func (s *SyncPrefixMap[V]) Range(from, to string, callback KeyCallback[V]) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  s.m.Range(from, to, callback)
+}
+
+// Seek iterates in ascending lexicographic order over the keys
+// greater than or equal to the given one, holding the read lock
+func (s *SyncPrefixMap[V]) Seek(key string, callback KeyCallback[V]) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  s.m.Seek(key, callback)
+}
+
+// Floor returns the greatest key in the map less
+// than or equal to the given one, along with its values
+func (s *SyncPrefixMap[V]) Floor(key string) (floor string, values []V, ok bool) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Floor(key)
+}
+
+// Ceiling returns the least key in the map greater
+// than or equal to the given one, along with its values
+func (s *SyncPrefixMap[V]) Ceiling(key string) (ceiling string, values []V, ok bool) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Ceiling(key)
+}
+
+// Predecessor returns the greatest key in the map
+// less than the given one, along with its values
+func (s *SyncPrefixMap[V]) Predecessor(key string) (predecessor string, values []V, ok bool) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Predecessor(key)
+}
+
+// Successor returns the least key in the map
+// greater than the given one, along with its values
+func (s *SyncPrefixMap[V]) Successor(key string) (successor string, values []V, ok bool) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Successor(key)
+}
+
+// Min returns the least key in the map, along with its values
+func (s *SyncPrefixMap[V]) Min() (key string, values []V, ok bool) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Min()
+}
+
+// Max returns the greatest key in the map, along with its values
+func (s *SyncPrefixMap[V]) Max() (key string, values []V, ok bool) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Max()
+}
+
+// All returns an iterator over the keys in the map and their
+// values, in ascending lexicographic order, holding the read lock
+func (s *SyncPrefixMap[V]) All() iter.Seq2[string, []V] {
+  return func(yield func(string, []V) bool) {
+    s.Ascend(yield)
+  }
+}
+
+// Keys returns an iterator over the keys in the map, in
+// ascending lexicographic order, holding the read lock
+func (s *SyncPrefixMap[V]) Keys() iter.Seq[string] {
+  return func(yield func(string) bool) {
+    s.Ascend(func(key string, _ []V) bool {
+      return yield(key)
+    })
+  }
+}
+
+// Values returns an iterator over the values associated with
+// each key in the map, in ascending key order, holding the read lock
+func (s *SyncPrefixMap[V]) Values() iter.Seq[[]V] {
+  return func(yield func([]V) bool) {
+    s.Ascend(func(_ string, values []V) bool {
+      return yield(values)
+    })
+  }
+}
+
+// WithPrefix returns an iterator over the keys starting with the
+// given prefix and their values, in ascending lexicographic order,
+// holding the read lock
+func (s *SyncPrefixMap[V]) WithPrefix(prefix string) iter.Seq2[string, []V] {
+  return func(yield func(string, []V) bool) {
+    s.AscendPrefix(prefix, yield)
+  }
+}
+
+// TopK returns the k keys starting with the given
+// prefix having the greatest weights
+func (s *SyncPrefixMap[V]) TopK(prefix string, k int) []Prefix[V] {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.TopK(prefix, k)
+}
+
+// FuzzySearch returns the keys within the given edit
+// distance from the query
+func (s *SyncPrefixMap[V]) FuzzySearch(query string, maxDistance int, opts ...FuzzyOption) []FuzzyMatch[V] {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.FuzzySearch(query, maxDistance, opts...)
+}
+
+// FuzzyPrefix returns the keys starting with a prefix
+// within the given edit distance from the query
+func (s *SyncPrefixMap[V]) FuzzyPrefix(query string, maxDistance int, opts ...FuzzyOption) []FuzzyMatch[V] {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.FuzzyPrefix(query, maxDistance, opts...)
+}
+
+// Match returns the keys in the map matching the given glob pattern
+func (s *SyncPrefixMap[V]) Match(pattern string) ([]Prefix[V], error) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Match(pattern)
+}
+
+// MatchRegexp returns the keys in the map entirely
+// matching the given regular expression
+func (s *SyncPrefixMap[V]) MatchRegexp(re *syntax.Regexp) ([]Prefix[V], error) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.MatchRegexp(re)
+}
+
+// MatchTopic returns the values of the keys
+// matching the given MQTT-style topic
+func (s *SyncPrefixMap[V]) MatchTopic(topic string) []V {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.MatchTopic(topic)
+}
+
+// Resolve returns the key the given abbreviation
+// stands for, along with its values
+func (s *SyncPrefixMap[V]) Resolve(abbrev string) (key string, values []V, err error) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Resolve(abbrev)
+}
+
+// ShortestUniquePrefixes returns the shortest abbreviation
+// Resolve resolves to each key in the map, by key
+func (s *SyncPrefixMap[V]) ShortestUniquePrefixes() map[string]string {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.ShortestUniquePrefixes()
+}
+
+// Compile builds a Scanner finding the occurrences of the keys
+// of the map in a text. The scanner can be used concurrently
+// with writes to the map.
+func (s *SyncPrefixMap[V]) Compile() *Scanner[V] {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Compile()
+}
+
+// Tokenize splits the given input into tokens, repeatedly
+// taking the longest key in the map at the current position
+func (s *SyncPrefixMap[V]) Tokenize(input string, unknown []V) []Token[V] {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  return s.m.Tokenize(input, unknown)
+}
+
+// TokenizeFunc is like Tokenize, invoking fn for each
+// token instead of collecting them, holding the read lock
+func (s *SyncPrefixMap[V]) TokenizeFunc(input string, unknown []V, fn func(token Token[V]) bool) {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+  s.m.TokenizeFunc(input, unknown, fn)
+}
@@ -0,0 +1,95 @@
+package prefixmap
+
+import (
+  "fmt"
+  "sync"
+  "testing"
+)
+
+func TestSyncConcurrentAccess(t *testing.T) {
+  s := NewSync[int]()
+  const writers, readers, keys = 4, 4, 200
+
+  var wg sync.WaitGroup
+  for w := 0; w < writers; w++ {
+    wg.Add(1)
+    go func(w int) {
+      defer wg.Done()
+      for i := 0; i < keys; i++ {
+        key := fmt.Sprintf("key/%d/%d", w, i)
+        s.Insert(key, i)
+        if i%2 == 0 {
+          s.Replace(key, i, i)
+        }
+      }
+    }(w)
+  }
+
+  for r := 0; r < readers; r++ {
+    wg.Add(1)
+    go func(r int) {
+      defer wg.Done()
+      for i := 0; i < keys; i++ {
+        s.GetByPrefix(fmt.Sprintf("key/%d/", r))
+        s.Get(fmt.Sprintf("key/%d/%d", r, i))
+        s.LongestPrefix(fmt.Sprintf("key/%d/%d/more", r, i))
+
+        prefixes := 0
+        s.EachPrefix(func(prefix Prefix[int]) (bool, bool) {
+          prefixes++
+          return false, false
+        })
+
+        // the iteration is consistent: every key is seen once
+        count, seen := 0, 0
+        s.View(func(m *PrefixMap[int]) {
+          count = m.Len()
+          for range m.All() {
+            seen++
+          }
+        })
+        if seen != count {
+          t.Errorf("Inconsistent iteration: seen %d keys, expected %d", seen, count)
+        }
+      }
+    }(r)
+  }
+  wg.Wait()
+
+  if s.Len() != writers*keys {
+    t.Errorf("Unexpected length: got %d, expected %d", s.Len(), writers*keys)
+  }
+  for w := 0; w < writers; w++ {
+    if values := s.GetByPrefix(fmt.Sprintf("key/%d/", w)); len(values) != keys+keys/2 {
+      t.Errorf("Unexpected number of values for writer %d: got %d", w, len(values))
+    }
+  }
+}
+
+func TestSyncIterators(t *testing.T) {
+  s := NewSync[string]()
+  for _, word := range nodeTests[0].words {
+    s.Insert(word, word)
+  }
+
+  keys := []string{}
+  for key := range s.WithPrefix("rub") {
+    keys = append(keys, key)
+  }
+  expected := []string{"rubens", "ruber", "rubicon", "rubicundus"}
+  if !testStringsEq(keys, expected) {
+    t.Errorf("Unexpected keys: got %v, expected %v", keys, expected)
+  }
+
+  // the read lock is released when the iteration breaks
+  for range s.Keys() {
+    break
+  }
+  s.Update(func(m *PrefixMap[string]) {
+    m.DeletePrefix("rom")
+    m.Insert("romulus", "romulus")
+  })
+  if s.CountPrefix("rom") != 1 {
+    t.Errorf("Expected the update to be applied")
+  }
+}
@@ -0,0 +1,63 @@
+package prefixmap
+
+import (
+  "unicode/utf8"
+)
+
+// Token is a span of the input of Tokenize
+type Token[V any] struct {
+  Start, End int    // the token is input[Start:End]
+  Text       string // input[Start:End]
+  Values     []V    // the values of the key matched, if Known
+
+  // Known tells if the token is a key in the map
+  // or a single rune matching no key
+  Known bool
+}
+
+// Tokenize splits the given input into tokens, repeatedly taking
+// the longest key in the map at the current position.
+// When no key matches, the token is the rune at the current
+// position, with the given unknown values.
+// The input is matched as is against the keys as stored, that
+// is normalized if the map normalizes them, and the empty key
+// is never matched.
+func (m *PrefixMap[V]) Tokenize(input string, unknown []V) []Token[V] {
+  tokens := []Token[V]{}
+  m.TokenizeFunc(input, unknown, func(token Token[V]) bool {
+    tokens = append(tokens, token)
+    return true
+  })
+  return tokens
+}
+
+// TokenizeFunc is like Tokenize, invoking fn for each token
+// instead of collecting them, without allocating.
+// Returning false stops the tokenization.
+func (m *PrefixMap[V]) TokenizeFunc(input string, unknown []V, fn func(token Token[V]) bool) {
+  mNode := (*Node[V])(m)
+  for start := 0; start < len(input); {
+    var match *Node[V]
+    length := 0
+    mNode.eachKeyPrefixOf(input[start:], func(node *Node[V], l int) bool {
+      if l > 0 {
+        match, length = node, l
+      }
+      return true
+    })
+
+    token := Token[V]{Start: start, Known: match != nil}
+    if match != nil {
+      token.End, token.Values = start+length, match.data
+    } else {
+      _, size := utf8.DecodeRuneInString(input[start:])
+      token.End, token.Values = start+size, unknown
+    }
+    token.Text = input[token.Start:token.End]
+
+    if !fn(token) {
+      return
+    }
+    start = token.End
+  }
+}
@@ -0,0 +1,74 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+var tokenizerKeys = []string{"=", "==", "!=", "if", "iff", " ", "+", "++"}
+
+func TestTokenize(t *testing.T) {
+  m := NewOf[string]()
+  for _, key := range tokenizerKeys {
+    m.Insert(key, key)
+  }
+
+  testCases := []struct {
+    input    string
+    expected []string
+    known    []bool
+  }{
+    {"if a == b", []string{"if", " ", "a", " ", "==", " ", "b"}, []bool{true, true, false, true, true, true, false}},
+    {"iff+++", []string{"iff", "++", "+"}, []bool{true, true, true}},
+    {"!==", []string{"!=", "="}, []bool{true, true}},
+    {"!ì=", []string{"!", "ì", "="}, []bool{false, false, true}},
+    {"", []string{}, []bool{}},
+  }
+
+  for _, tc := range testCases {
+    tokens := m.Tokenize(tc.input, []string{"unknown"})
+    texts := []string{}
+    for i, token := range tokens {
+      texts = append(texts, token.Text)
+      if token.Text != tc.input[token.Start:token.End] {
+        t.Errorf("Unexpected span for token '%s': [%d, %d)", token.Text, token.Start, token.End)
+      }
+      if i < len(tc.known) && token.Known != tc.known[i] {
+        t.Errorf("Unexpected token '%s' in '%s': known %v", token.Text, tc.input, token.Known)
+      }
+      if token.Known && token.Values[0] != token.Text || !token.Known && token.Values[0] != "unknown" {
+        t.Errorf("Unexpected values for token '%s': %v", token.Text, token.Values)
+      }
+    }
+    if !testStringsEq(texts, tc.expected) {
+      t.Errorf("Unexpected tokens for '%s': got %v, expected %v", tc.input, texts, tc.expected)
+    }
+  }
+}
+
+func TestTokenizeFunc(t *testing.T) {
+  m := NewOf[string]()
+  for _, key := range tokenizerKeys {
+    m.Insert(key, key)
+  }
+
+  texts := []string{}
+  m.TokenizeFunc("if a == b", nil, func(token Token[string]) bool {
+    texts = append(texts, token.Text)
+    return token.Text != "a"
+  })
+  if !testStringsEq(texts, []string{"if", " ", "a"}) {
+    t.Errorf("Expected the tokenization to stop: got %v", texts)
+  }
+
+  input := "if a == b != c++ iff d"
+  count := 0
+  allocs := testing.AllocsPerRun(100, func() {
+    m.TokenizeFunc(input, nil, func(token Token[string]) bool {
+      count++
+      return true
+    })
+  })
+  if allocs != 0 {
+    t.Errorf("Expected no allocations, got %v", allocs)
+  }
+}
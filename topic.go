@@ -0,0 +1,102 @@
+package prefixmap
+
+import (
+  "sort"
+  "strings"
+)
+
+// MatchTopic returns the values of the keys matching the given
+// topic, treating keys as MQTT-style topic filters made of levels
+// separated by '/': a "+" level matches exactly one topic level and
+// a trailing "#" level matches any number of them, including none,
+// so that "a/#" matches "a", "a/b" and "a/b/c".
+// As in MQTT, the topics starting with '$' are not matched by
+// filters starting with a wildcard.
+// Values are returned sorted by key.
+func (m *PrefixMap[V]) MatchTopic(topic string) []V {
+  t := &topicMatch[V]{
+    topic:  m.opts.normalize(topic),
+    values: []V{},
+  }
+
+  mNode := (*Node[V])(m)
+  t.walk(mNode, 0, 0, true)
+
+  return t.values
+}
+
+// topicMatch holds the state of a topic matching
+type topicMatch[V any] struct {
+  topic  string
+  values []V
+}
+
+// walk matches the keys continuing from the given offset within
+// the key of node against the topic from pos onwards.
+// level tells if the offset is at the start of a filter level.
+func (t *topicMatch[V]) walk(node *Node[V], offset, pos int, level bool) {
+  for ; offset < len(node.key); offset++ {
+    b := node.key[offset]
+    switch {
+    case level && b == '+':
+      if pos == 0 && strings.HasPrefix(t.topic, "$") {
+        return
+      }
+      if i := strings.IndexByte(t.topic[pos:], '/'); i >= 0 {
+        pos += i
+      } else {
+        pos = len(t.topic)
+      }
+    case level && b == '#':
+      if pos == 0 && strings.HasPrefix(t.topic, "$") {
+        return
+      }
+      if offset == len(node.key)-1 && node.isKey {
+        t.values = append(t.values, node.data...)
+      }
+      return
+    case pos == len(t.topic):
+      // "a/#" also matches the parent level "a"
+      if b == '/' {
+        if next, offset := node.advance(offset+1, "#"); next != nil && offset == len(next.key) && next.isKey {
+          t.values = append(t.values, next.data...)
+        }
+      }
+      return
+    case t.topic[pos] == b:
+      pos++
+    default:
+      return
+    }
+    level = b == '/'
+  }
+
+  if pos == len(t.topic) && node.isKey {
+    t.values = append(t.values, node.data...)
+  }
+
+  // the children possibly matching: the wildcards and the one
+  // continuing with the topic, or possibly with "/#" at its end
+  children := make([]*Node[V], 0, 3)
+  if level {
+    for _, wildcard := range []string{"#", "+"} {
+      if c := node.childFor(wildcard); c != nil {
+        children = append(children, c)
+      }
+    }
+  }
+  if pos < len(t.topic) {
+    if c := node.childFor(t.topic[pos:]); c != nil && !(level && (c.key[0] == '#' || c.key[0] == '+')) {
+      children = append(children, c)
+    }
+  } else if c := node.childFor("/"); c != nil {
+    children = append(children, c)
+  }
+  sort.Slice(children, func(i, j int) bool {
+    return children[i].key < children[j].key
+  })
+
+  for _, c := range children {
+    t.walk(c, 0, pos, level)
+  }
+}
@@ -0,0 +1,59 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestMatchTopic(t *testing.T) {
+  m := NewOf[string]()
+  for _, filter := range []string{
+    "#",
+    "+/+",
+    "sport/#",
+    "sport/+",
+    "sport/+/player1",
+    "sport/tennis/#",
+    "sport/tennis/player1",
+    "sport/tennis+",
+    "+/tennis/#",
+    "$SYS/#",
+    "/+",
+  } {
+    m.Insert(filter, filter)
+  }
+
+  testCases := []struct {
+    topic    string
+    expected []string
+  }{
+    {"sport", []string{"#", "sport/#"}},
+    {"sport/", []string{"#", "+/+", "sport/#", "sport/+"}},
+    {"sport/tennis", []string{"#", "+/+", "+/tennis/#", "sport/#", "sport/+", "sport/tennis/#"}},
+    {"sport/tennis/player1", []string{"#", "+/tennis/#", "sport/#", "sport/+/player1", "sport/tennis/#", "sport/tennis/player1"}},
+    {"sport/tennis+", []string{"#", "+/+", "sport/#", "sport/+", "sport/tennis+"}},
+    {"sport/tennisx", []string{"#", "+/+", "sport/#", "sport/+"}},
+    {"/finance", []string{"#", "+/+", "/+"}},
+    {"$SYS/uptime", []string{"$SYS/#"}},
+    {"$SYS", []string{"$SYS/#"}},
+  }
+
+  for _, tc := range testCases {
+    values := m.MatchTopic(tc.topic)
+    if !testStringsEq(values, tc.expected) {
+      t.Errorf("Unexpected filters matching '%s': got %v, expected %v", tc.topic, values, tc.expected)
+    }
+  }
+}
+
+func TestMatchTopicRuneAware(t *testing.T) {
+  m := NewOf[string](RuneAware())
+  for _, filter := range []string{"città/+", "città/#", "cittadella"} {
+    m.Insert(filter, filter)
+  }
+
+  values := m.MatchTopic("città/roma")
+  expected := []string{"città/#", "città/+"}
+  if !testStringsEq(values, expected) {
+    t.Errorf("Unexpected filters matching: got %v, expected %v", values, expected)
+  }
+}
@@ -0,0 +1,126 @@
+package prefixmap
+
+import (
+  "container/heap"
+  "math"
+)
+
+// InsertWeighted inserts the given values in the map for the specified
+// key, like Insert, and sets the weight of the key to the given one.
+// Weights are used by TopK to rank completions; keys inserted
+// with Insert or Replace weigh 0 unless specified otherwise.
+func (m *PrefixMap[V]) InsertWeighted(key string, weight float64, values ...V) {
+  mNode := (*Node[V])(m)
+  n, _ := mNode.nodeForKey(m.opts.normalize(key), true)
+  n.data = append(n.data, values...)
+  n.setKey(key)
+  n.weight = weight
+  n.updateWeights()
+}
+
+// TopK returns the k highest-weighted keys starting
+// with the given prefix, heaviest first.
+// Keys with the same weight are sorted lexicographically.
+//
+// Each node caches the maximum weight within its subtree so that
+// the search only visits the branches that can still contribute
+// to the result.
+func (m *PrefixMap[V]) TopK(prefix string, k int) []Prefix[V] {
+  mNode := (*Node[V])(m)
+  completions := []Prefix[V]{}
+  n := mNode.nodeForPrefix(m.opts.normalize(prefix))
+  if n == nil || k <= 0 {
+    return completions
+  }
+
+  // best-first search: subtrees are ranked by their maximum
+  // weight, which no key within them can exceed
+  candidates := &weightHeap[V]{}
+  heap.Push(candidates, weightEntry[V]{node: n, key: n.Key(), weight: n.maxWeight})
+  for candidates.Len() > 0 && len(completions) < k {
+    e := heap.Pop(candidates).(weightEntry[V])
+    if e.isKey {
+      completions = append(completions, Prefix[V]{
+        node:   e.node,
+        Key:    e.node.keyOf(e.key),
+        Values: e.node.data,
+      })
+      continue
+    }
+
+    if e.node.isKey {
+      heap.Push(candidates, weightEntry[V]{node: e.node, key: e.key, weight: e.node.weight, isKey: true})
+    }
+    for _, c := range e.node.Children {
+      heap.Push(candidates, weightEntry[V]{node: c, key: e.key + c.key, weight: c.maxWeight})
+    }
+  }
+
+  return completions
+}
+
+// Weight returns the weight of the key
+// corresponding to this prefix
+func (p *Prefix[V]) Weight() float64 {
+  return p.node.weight
+}
+
+// updateWeights recomputes the maximum subtree weight
+// of m and of its ancestors, as long as it changes
+func (m *Node[V]) updateWeights() {
+  for node := m; node != nil; node = node.Parent {
+    maxWeight := math.Inf(-1)
+    if node.isKey {
+      maxWeight = node.weight
+    }
+    for _, c := range node.Children {
+      maxWeight = math.Max(maxWeight, c.maxWeight)
+    }
+    if node != m && node.maxWeight == maxWeight {
+      return
+    }
+    node.maxWeight = maxWeight
+  }
+}
+
+// weightEntry is either a key or a whole
+// subtree candidate for the TopK search
+type weightEntry[V any] struct {
+  node   *Node[V]
+  key    string
+  weight float64
+  isKey  bool
+}
+
+// weightHeap implements heap.Interface for TopK
+type weightHeap[V any] []weightEntry[V]
+
+func (h weightHeap[V]) Len() int {
+  return len(h)
+}
+
+func (h weightHeap[V]) Less(i, j int) bool {
+  if h[i].weight != h[j].weight {
+    return h[i].weight > h[j].weight
+  }
+  if h[i].key != h[j].key {
+    return h[i].key < h[j].key
+  }
+  // a key precedes the subtree it is the root of
+  return h[i].isKey && !h[j].isKey
+}
+
+func (h weightHeap[V]) Swap(i, j int) {
+  h[i], h[j] = h[j], h[i]
+}
+
+func (h *weightHeap[V]) Push(x any) {
+  *h = append(*h, x.(weightEntry[V]))
+}
+
+func (h *weightHeap[V]) Pop() any {
+  old := *h
+  e := old[len(old)-1]
+  *h = old[:len(old)-1]
+  return e
+}
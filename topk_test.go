@@ -0,0 +1,82 @@
+package prefixmap
+
+import (
+  "testing"
+)
+
+func TestTopK(t *testing.T) {
+  weights := map[string]float64{
+    "car":      3,
+    "card":     10,
+    "care":     7,
+    "careful":  7,
+    "carpet":   1,
+    "cart":     -2,
+    "cat":      8,
+    "category": 12,
+    "dog":      100,
+  }
+
+  m := New()
+  for key, weight := range weights {
+    m.InsertWeighted(key, weight, key)
+  }
+
+  testCases := []struct {
+    prefix   string
+    k        int
+    expected []string
+  }{
+    {"ca", 3, []string{"category", "card", "cat"}},
+    {"car", 4, []string{"card", "care", "careful", "car"}},
+    {"car", 10, []string{"card", "care", "careful", "car", "carpet", "cart"}},
+    {"", 1, []string{"dog"}},
+    {"x", 3, []string{}},
+    {"ca", 0, []string{}},
+  }
+
+  for _, tc := range testCases {
+    keys := []string{}
+    for _, p := range m.TopK(tc.prefix, tc.k) {
+      if p.Weight() != weights[p.Key] {
+        t.Errorf("Unexpected weight for key '%s': got %v, expected %v", p.Key, p.Weight(), weights[p.Key])
+      }
+      keys = append(keys, p.Key)
+    }
+    if !testStringsEq(keys, tc.expected) {
+      t.Errorf("Unexpected top %d for prefix '%s': got %v, expected %v", tc.k, tc.prefix, keys, tc.expected)
+    }
+  }
+}
+
+func TestTopKMaxWeight(t *testing.T) {
+  m := New()
+  m.InsertWeighted("card", 10, "card")
+  m.InsertWeighted("care", 5, "care")
+  m.InsertWeighted("cat", 1, "cat")
+  n := (*Node[any])(m)
+
+  if n.maxWeight != 10 {
+    t.Errorf("Unexpected root max weight: got %v, expected %v", n.maxWeight, 10)
+  }
+
+  // lowering the weight of the heaviest key
+  m.InsertWeighted("card", 2)
+  if n.maxWeight != 5 {
+    t.Errorf("Unexpected root max weight: got %v, expected %v", n.maxWeight, 5)
+  }
+
+  m.Delete("care")
+  if n.maxWeight != 2 {
+    t.Errorf("Unexpected root max weight after deletion: got %v, expected %v", n.maxWeight, 2)
+  }
+
+  m.DeletePrefix("car")
+  if n.maxWeight != 1 {
+    t.Errorf("Unexpected root max weight after prefix deletion: got %v, expected %v", n.maxWeight, 1)
+  }
+
+  if top := m.TopK("c", 2); len(top) != 1 || top[0].Key != "cat" {
+    t.Errorf("Unexpected top keys: got %v", top)
+  }
+}